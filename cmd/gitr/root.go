@@ -10,11 +10,14 @@ import (
 	"github.com/swarupdonepudi/gitr/cmd/gitr/root"
 	"github.com/swarupdonepudi/gitr/internal/cli"
 	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/redact"
 	"github.com/swarupdonepudi/gitr/pkg/ui"
 )
 
 var debug bool
 
+var configPath string
+
 const HomebrewAppleSiliconBinPath = "/opt/homebrew/bin"
 
 var rootCmd = &cobra.Command{
@@ -37,12 +40,16 @@ Learn more: https://swarupdonepudi.github.io/gitr`,
 }
 
 func init() {
+	log.SetFormatter(redact.NewFormatter(log.StandardLogger().Formatter))
 	rootCmd.PersistentFlags().BoolVar(&debug, string(cli.Debug), false, "set log level to debug")
 	rootCmd.PersistentFlags().BoolP(string(cli.Dry), "", false, "dry run")
+	rootCmd.PersistentFlags().StringVar(&configPath, string(cli.Config), "",
+		"path of the config file, defaults to $GITR_CONFIG, $XDG_CONFIG_HOME/gitr/config.yaml when it exists or ~/.gitr.yaml")
 	rootCmd.AddCommand(
 		root.Version,
 		root.Config,
 		root.Clone,
+		root.CloneOrg,
 		root.Path,
 		root.BranchesCmd,
 		root.CommitsCmd,
@@ -66,10 +73,11 @@ func init() {
 				ui.GenericError("Environment Error", "Failed to configure PATH for Apple Silicon", err)
 			}
 		}
+		config.SetConfigPath(configPath)
+		if err := config.EnsureInitialConfig(); err != nil {
+			ui.GenericError("Configuration Error", "Failed to initialize gitr configuration", err)
+		}
 	})
-	if err := config.EnsureInitialConfig(); err != nil {
-		ui.GenericError("Configuration Error", "Failed to initialize gitr configuration", err)
-	}
 }
 
 func Execute() {
@@ -1,7 +1,10 @@
 package root
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/atotto/clipboard"
 	"github.com/spf13/cobra"
@@ -11,33 +14,133 @@ import (
 	"github.com/swarupdonepudi/gitr/pkg/ui"
 )
 
+// stdinArg is the argument used in place of a url to read repo urls from stdin
+const stdinArg = "-"
+
 var Clone = &cobra.Command{
 	Use:   "clone",
 	Short: "Clone repo to organized, deterministic path (~/scm/{host}/{owner}/{repo})",
-	Run:   cloneHandler,
+	Long: `Clone repo to organized, deterministic path (~/scm/{host}/{owner}/{repo})
+
+Repos can also be referenced as owner/repo, resolved against the defaultHost of the config,
+or as alias:owner/repo, where the alias is one of the aliases of the config or gh, gl and bb.
+
+Many repositories can be cloned in parallel by passing a file with one url per line,
+or by passing - to read the urls from stdin.
+
+Examples:
+  gitr clone https://github.com/owner/repo
+  gitr clone gh:owner/repo
+  gitr clone --fork https://github.com/owner/repo
+  gitr clone -f repos.txt --jobs 8
+  cat repos.txt | gitr clone -`,
+	Run: cloneHandler,
 }
 
 func init() {
 	Clone.PersistentFlags().BoolP(string(cli.CreDir), "", false, "create full directory hierarchy matching SCM structure")
 	Clone.PersistentFlags().StringP(string(cli.Token), "", "", "HTTPS personal access token for authentication")
+	Clone.PersistentFlags().StringP(string(cli.File), "f", "", "file with repo urls to clone, one per line (use - for stdin)")
+	Clone.PersistentFlags().IntP(string(cli.Jobs), "j", clone.DefaultJobs, "number of repos to clone in parallel when cloning from a list")
+	Clone.PersistentFlags().BoolP(string(cli.Fork), "", false, "fork the repo under your account, clone the fork and add the repo as upstream remote")
+	addCloneShapeFlags(Clone)
 }
 
-func cloneHandler(cmd *cobra.Command, args []string) {
-	if len(args) <= 0 {
-		ui.CloneURLRequired()
-	}
-	inputUrl := args[0]
+// addCloneShapeFlags adds the flags that control how much of a repository is fetched and checked out,
+// and what happens to a directory that is in the way of the clone
+func addCloneShapeFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().IntP(string(cli.Depth), "", 0, "create a shallow clone with history truncated to the number of commits")
+	cmd.PersistentFlags().StringP(string(cli.Filter), "", "", "partial clone filter, ex: blob:none or tree:0")
+	cmd.PersistentFlags().BoolP(string(cli.SingleBranch), "", false, "clone only the history of the default branch")
+	cmd.PersistentFlags().StringSliceP(string(cli.Sparse), "", nil, "check out only the given directories (comma separated)")
+	cmd.PersistentFlags().StringP(string(cli.ExistingDir), "", "", "what to do when the clone path is a directory that is not a git repo: fail, backup, prompt or overwrite")
+	cmd.PersistentFlags().StringP(string(cli.Submodules), "", "", "clone submodules in place (recurse), or to their own gitr paths linked as alternates (managed)")
+	cmd.PersistentFlags().Lookup(string(cli.Submodules)).NoOptDefVal = string(config.SubmodulesRecurse)
+	cmd.PersistentFlags().StringP(string(cli.Lfs), "", "", "download git lfs objects after the clone (pull) or keep the pointer files (skip)")
+}
+
+// getCloneOptions reads the clone options from the flags of the command
+func getCloneOptions(cmd *cobra.Command) *clone.Options {
 	dry, err := cmd.InheritedFlags().GetBool(string(cli.Dry))
 	cli.HandleFlagErr(err, cli.Dry)
 	creDir, err := cmd.PersistentFlags().GetBool(string(cli.CreDir))
 	cli.HandleFlagErr(err, cli.CreDir)
 	token, err := cmd.PersistentFlags().GetString(string(cli.Token))
 	cli.HandleFlagErr(err, cli.Token)
+	depth, err := cmd.PersistentFlags().GetInt(string(cli.Depth))
+	cli.HandleFlagErr(err, cli.Depth)
+	filter, err := cmd.PersistentFlags().GetString(string(cli.Filter))
+	cli.HandleFlagErr(err, cli.Filter)
+	singleBranch, err := cmd.PersistentFlags().GetBool(string(cli.SingleBranch))
+	cli.HandleFlagErr(err, cli.SingleBranch)
+	sparsePaths, err := cmd.PersistentFlags().GetStringSlice(string(cli.Sparse))
+	cli.HandleFlagErr(err, cli.Sparse)
+	onExistingDir, err := cmd.PersistentFlags().GetString(string(cli.ExistingDir))
+	cli.HandleFlagErr(err, cli.ExistingDir)
+	submodules, err := cmd.PersistentFlags().GetString(string(cli.Submodules))
+	cli.HandleFlagErr(err, cli.Submodules)
+	lfs, err := cmd.PersistentFlags().GetString(string(cli.Lfs))
+	cli.HandleFlagErr(err, cli.Lfs)
+	explicit := make(map[clone.Option]bool)
+	for flag, option := range map[cli.Flag]clone.Option{
+		cli.Depth:        clone.OptionDepth,
+		cli.Filter:       clone.OptionFilter,
+		cli.SingleBranch: clone.OptionSingleBranch,
+		cli.Sparse:       clone.OptionSparsePaths,
+		cli.ExistingDir:  clone.OptionOnExistingDir,
+		cli.Submodules:   clone.OptionSubmodules,
+		cli.Lfs:          clone.OptionLfs,
+	} {
+		explicit[option] = cmd.PersistentFlags().Changed(string(flag))
+	}
+	return &clone.Options{
+		Token:         token,
+		CreDir:        creDir,
+		Dry:           dry,
+		Depth:         depth,
+		Filter:        filter,
+		SingleBranch:  singleBranch,
+		SparsePaths:   sparsePaths,
+		OnExistingDir: config.ExistingDirPolicy(onExistingDir),
+		Submodules:    config.SubmoduleMode(submodules),
+		Lfs:           config.LfsMode(lfs),
+		Explicit:      explicit,
+	}
+}
+
+func cloneHandler(cmd *cobra.Command, args []string) {
+	listFile, err := cmd.PersistentFlags().GetString(string(cli.File))
+	cli.HandleFlagErr(err, cli.File)
+	if listFile == "" && len(args) > 0 && args[0] == stdinArg {
+		listFile = stdinArg
+	}
+	if listFile != "" {
+		bulkCloneHandler(cmd, listFile)
+		return
+	}
+	if len(args) <= 0 {
+		ui.CloneURLRequired()
+	}
+	inputUrl := args[0]
+	opts := getCloneOptions(cmd)
+	fork, err := cmd.PersistentFlags().GetBool(string(cli.Fork))
+	cli.HandleFlagErr(err, cli.Fork)
 	cfg, err := config.NewGitrConfig()
 	if err != nil {
 		ui.ConfigError(err)
 	}
-	clonePath, err := clone.Clone(cfg, inputUrl, token, creDir, dry)
+	var clonePath string
+	if fork {
+		clonePath, err = clone.CloneFork(cfg, inputUrl, opts)
+	} else {
+		clonePath, err = clone.Clone(cfg, inputUrl, opts)
+	}
+	if errors.Is(err, clone.ErrDirtyWorktree) {
+		ui.DirtyWorktree(clonePath)
+	}
+	if errors.Is(err, clone.ErrExistingDir) {
+		ui.ExistingDirNotRepo(clonePath)
+	}
 	if err != nil {
 		ui.FailedToClone(err)
 	}
@@ -53,3 +156,65 @@ func cloneHandler(cmd *cobra.Command, args []string) {
 
 	ui.CloneSuccess(clonePath, clipboardEnabled)
 }
+
+func bulkCloneHandler(cmd *cobra.Command, listFile string) {
+	fork, err := cmd.PersistentFlags().GetBool(string(cli.Fork))
+	cli.HandleFlagErr(err, cli.Fork)
+	if fork {
+		ui.ForkWithUrlList()
+	}
+	opts := getCloneOptions(cmd)
+	jobs, err := cmd.PersistentFlags().GetInt(string(cli.Jobs))
+	cli.HandleFlagErr(err, cli.Jobs)
+
+	var r io.Reader = os.Stdin
+	source := "stdin"
+	if listFile != stdinArg {
+		f, err := os.Open(listFile)
+		if err != nil {
+			ui.FileNotFound(listFile)
+		}
+		defer f.Close()
+		r = f
+		source = listFile
+	}
+	inputUrls, err := clone.ReadUrlList(r)
+	if err != nil {
+		ui.GenericError("Failed to Read Repositories", fmt.Sprintf("Could not read repository urls from %s", source), err)
+	}
+	if len(inputUrls) == 0 {
+		ui.CloneURLListEmpty(source)
+	}
+
+	cfg, err := config.NewGitrConfig()
+	if err != nil {
+		ui.ConfigError(err)
+	}
+	if opts.Dry {
+		for _, inputUrl := range inputUrls {
+			if _, err := clone.Clone(cfg, inputUrl, opts); err != nil {
+				ui.FailedToClone(err)
+			}
+		}
+		return
+	}
+
+	runBulkClone(cfg, inputUrls, opts, jobs)
+}
+
+// runBulkClone clones the urls in parallel, prints a summary and exits with a non-zero code if any clone failed
+func runBulkClone(cfg *config.GitrConfig, inputUrls []string, opts *clone.Options, jobs int) {
+	ui.BulkCloning(len(inputUrls), jobs)
+	ui.SetQuiet(true)
+	results := clone.CloneAll(cfg, inputUrls, opts, jobs)
+	ui.SetQuiet(false)
+	clone.PrintBulkCloneSummary(results)
+
+	if failed := clone.CountBulkResults(results, clone.BulkFailed); failed > 0 {
+		ui.BulkCloneFailed(failed, len(results))
+	}
+	ui.BulkCloneSuccess(
+		clone.CountBulkResults(results, clone.BulkCloned),
+		clone.CountBulkResults(results, clone.BulkAlreadyPresent),
+	)
+}
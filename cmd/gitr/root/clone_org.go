@@ -0,0 +1,137 @@
+package root
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/internal/cli"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	"github.com/swarupdonepudi/gitr/pkg/clone"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
+	"github.com/swarupdonepudi/gitr/pkg/scmapi"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+	"github.com/swarupdonepudi/gitr/pkg/url"
+)
+
+var CloneOrg = &cobra.Command{
+	Use:   "clone-org <host>/<org-or-group>",
+	Short: "Clone every repo of a GitHub org or GitLab group to organized, deterministic paths",
+	Long: `Clone every repo of a GitHub org or GitLab group to organized, deterministic paths
+
+Repositories are listed through the provider api and GitLab subgroups are included.
+Archived repos and forks are skipped unless requested. Repos that are already cloned are left untouched.
+
+Examples:
+  gitr clone-org github.com/kubernetes-sigs
+  gitr clone-org gitlab.com/gitlab-org/ci-cd --match 'runner' --jobs 8
+  gitr clone-org github.com/my-org --topic backend --forks`,
+	Args: cobra.ExactArgs(1),
+	Run:  cloneOrgHandler,
+}
+
+func init() {
+	CloneOrg.PersistentFlags().BoolP(string(cli.CreDir), "", false, "create full directory hierarchy matching SCM structure")
+	CloneOrg.PersistentFlags().StringP(string(cli.Token), "", "", "personal access token for the provider api and HTTPS clones")
+	CloneOrg.PersistentFlags().IntP(string(cli.Jobs), "j", clone.DefaultJobs, "number of repos to clone in parallel")
+	CloneOrg.PersistentFlags().BoolP(string(cli.Archived), "", false, "include archived repos")
+	CloneOrg.PersistentFlags().BoolP(string(cli.Forks), "", false, "include forked repos")
+	CloneOrg.PersistentFlags().StringP(string(cli.Match), "", "", "only clone repos whose full path matches the regular expression")
+	CloneOrg.PersistentFlags().StringP(string(cli.Topic), "", "", "only clone repos tagged with the topic")
+	addCloneShapeFlags(CloneOrg)
+}
+
+func cloneOrgHandler(cmd *cobra.Command, args []string) {
+	opts := getCloneOptions(cmd)
+	jobs, err := cmd.PersistentFlags().GetInt(string(cli.Jobs))
+	cli.HandleFlagErr(err, cli.Jobs)
+	archived, err := cmd.PersistentFlags().GetBool(string(cli.Archived))
+	cli.HandleFlagErr(err, cli.Archived)
+	forks, err := cmd.PersistentFlags().GetBool(string(cli.Forks))
+	cli.HandleFlagErr(err, cli.Forks)
+	match, err := cmd.PersistentFlags().GetString(string(cli.Match))
+	cli.HandleFlagErr(err, cli.Match)
+	topic, err := cmd.PersistentFlags().GetString(string(cli.Topic))
+	cli.HandleFlagErr(err, cli.Topic)
+
+	filter := &scmapi.Filter{IncludeArchived: archived, IncludeForks: forks, Topic: topic}
+	if match != "" {
+		if filter.NameRegex, err = regexp.Compile(match); err != nil {
+			ui.FlagParseError(string(cli.Match), err)
+		}
+	}
+
+	hostname, owner := parseOrgRef(args[0])
+	if hostname == "" || owner == "" {
+		ui.Error(
+			"Invalid Organization",
+			fmt.Sprintf("%s is not a valid organization reference.", ui.Path(args[0])),
+			"Usage: "+ui.Cmd("gitr clone-org <host>/<org-or-group>"),
+		)
+	}
+
+	cfg, err := config.NewGitrConfig()
+	if err != nil {
+		ui.ConfigError(err)
+	}
+	s, err := config.GetScmHost(cfg, hostname)
+	if err != nil {
+		ui.UnknownSCMHost(hostname)
+	}
+	chain, err := auth.NewChain(s, opts.Token)
+	if err != nil {
+		ui.GenericError("Authentication Error", "Failed to set up credentials", err)
+	}
+
+	repos, err := scmapi.ListRepos(s, owner, chain.Authenticate(auth.TransportHttp).Token())
+	if err != nil {
+		ui.GenericError("Failed to List Repositories", fmt.Sprintf("Could not list repositories of %s", owner), err)
+	}
+	prov, err := provider.ForHost(s)
+	if err != nil {
+		ui.GenericError("Unknown Provider", fmt.Sprintf("The provider of %s is not supported", s.Hostname), err)
+	}
+	scheme := s.Scheme
+	if scheme == "" {
+		scheme = config.Https
+	}
+	inputUrls := make([]string, 0)
+	for _, r := range repos {
+		if filter.Match(r) {
+			inputUrls = append(inputUrls, prov.RepoWebURL(scheme, s.Hostname, r.FullPath))
+		}
+	}
+	if len(inputUrls) == 0 {
+		ui.Warn("No Repositories to Clone", fmt.Sprintf("None of the %d repositories of %s matched the filters.", len(repos), owner))
+		return
+	}
+
+	if opts.Dry {
+		for _, inputUrl := range inputUrls {
+			clonePath, err := clone.GetClonePath(cfg, inputUrl, opts.CreDir)
+			if err != nil {
+				ui.FailedToClone(err)
+			}
+			fmt.Printf("%s -> %s\n", inputUrl, clonePath)
+		}
+		return
+	}
+
+	runBulkClone(cfg, inputUrls, opts, jobs)
+}
+
+// parseOrgRef splits references like github.com/org or https://gitlab.com/group/subgroup
+// into the hostname and the org or group path
+func parseOrgRef(ref string) (hostname, owner string) {
+	ref = strings.TrimSuffix(url.StripQueryParams(ref), "/")
+	if strings.Contains(ref, "://") {
+		hostname = url.GetHostname(ref)
+		ref = ref[strings.Index(ref, hostname)+len(hostname):]
+	} else if idx := strings.Index(ref, "/"); idx != -1 {
+		hostname = ref[:idx]
+		ref = ref[idx:]
+	}
+	return hostname, strings.Trim(ref, "/")
+}
@@ -11,5 +11,5 @@ var Config = &cobra.Command{
 }
 
 func init() {
-	Config.AddCommand(config.Init, config.Show, config.Edit)
+	Config.AddCommand(config.Init, config.Show, config.Edit, config.Validate, config.Host)
 }
@@ -1,13 +1,12 @@
 package config
 
 import (
-	"os"
 	"os/exec"
-	"path/filepath"
 
 	"github.com/leftbin/go-util/pkg/file"
 	"github.com/leftbin/go-util/pkg/shell"
 	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/pkg/config"
 	"github.com/swarupdonepudi/gitr/pkg/ui"
 )
 
@@ -18,11 +17,10 @@ var Edit = &cobra.Command{
 }
 
 func editHandler(cmd *cobra.Command, args []string) {
-	homeDir, err := os.UserHomeDir()
+	gitrConfigPath, err := config.GetConfigPath()
 	if err != nil {
-		ui.GenericError("System Error", "Failed to determine home directory", err)
+		ui.GenericError("Configuration Error", "Failed to determine the config file", err)
 	}
-	gitrConfigPath := filepath.Join(homeDir, ".gitr.yaml")
 	if !file.IsFileExists(gitrConfigPath) {
 		ui.Error(
 			"Configuration Not Found",
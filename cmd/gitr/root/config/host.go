@@ -0,0 +1,15 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/cmd/gitr/root/config/host"
+)
+
+var Host = &cobra.Command{
+	Use:   "host",
+	Short: "manage the scm hosts of gitr config",
+}
+
+func init() {
+	Host.AddCommand(host.Add, host.Remove, host.List, host.Set)
+}
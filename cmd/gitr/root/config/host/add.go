@@ -0,0 +1,49 @@
+package host
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/internal/cli"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+var Add = &cobra.Command{
+	Use:   "add <hostname>",
+	Short: "add an scm host to gitr config",
+	Example: `  gitr config host add gitlab.corp.net --provider gitlab --scheme https --always-cre-dir
+  gitr config host add bitbucket.corp.net --provider bitbucket --ssh-port 7999`,
+	Args: cobra.ExactArgs(1),
+	Run:  addHandler,
+}
+
+func init() {
+	addHostFlags(Add)
+}
+
+func addHandler(cmd *cobra.Command, args []string) {
+	if !cmd.PersistentFlags().Changed(string(cli.Provider)) {
+		ui.Error("Missing Provider", fmt.Sprintf("The provider of %s is required.", args[0]), "Pass it with "+ui.Cmd("--provider"))
+	}
+	s := &config.ScmHost{
+		Hostname:      args[0],
+		Provider:      config.ScmProvider(getHostFlagValue(cmd, cli.Provider).(string)),
+		Scheme:        config.HttpScheme(getHostFlagValue(cmd, cli.Scheme).(string)),
+		DefaultBranch: getHostFlagValue(cmd, cli.Branch).(string),
+		ApiUrl:        getHostFlagValue(cmd, cli.ApiUrl).(string),
+		SshUser:       getHostFlagValue(cmd, cli.SshUser).(string),
+		SshPort:       getHostFlagValue(cmd, cli.SshPort).(int),
+		Clone: &config.CloneConfig{
+			HomeDir:              getHostFlagValue(cmd, cli.HomeDir).(string),
+			AlwaysCreDir:         getHostFlagValue(cmd, cli.AlwaysCreDir).(bool),
+			IncludeHostForCreDir: getHostFlagValue(cmd, cli.IncludeHost).(bool),
+		},
+	}
+	f := loadConfigFile()
+	if err := f.AddHost(s); err != nil {
+		ui.GenericError("Failed to Add Host", fmt.Sprintf("Could not add %s", s.Hostname), err)
+	}
+	saveConfigFile(f)
+	ui.Success("Host added", fmt.Sprintf("%s was added to %s", s.Hostname, ui.Path(f.Path)))
+}
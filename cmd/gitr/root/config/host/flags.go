@@ -0,0 +1,75 @@
+package host
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/internal/cli"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+// hostKeys are the keys of a host in the config file set by the host flags
+var hostKeys = []struct {
+	flag cli.Flag
+	key  string
+}{
+	{cli.Provider, "provider"},
+	{cli.Scheme, "scheme"},
+	{cli.Branch, "defaultBranch"},
+	{cli.ApiUrl, "apiUrl"},
+	{cli.SshUser, "sshUser"},
+	{cli.SshPort, "sshPort"},
+	{cli.HomeDir, "clone.homeDir"},
+	{cli.AlwaysCreDir, "clone.alwaysCreDir"},
+	{cli.IncludeHost, "clone.includeHostForCreDir"},
+}
+
+func addHostFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(string(cli.Provider), "", "", "provider of the host, one of the built-in providers or a provider of the config")
+	cmd.PersistentFlags().StringP(string(cli.Scheme), "", string(config.Https), "scheme of the web and http clone urls, http or https")
+	cmd.PersistentFlags().StringP(string(cli.Branch), "", "main", "default branch of the repos on the host")
+	cmd.PersistentFlags().StringP(string(cli.ApiUrl), "", "", "url of the provider api, for hosts that do not serve it from the default location")
+	cmd.PersistentFlags().StringP(string(cli.SshUser), "", "", "user of ssh clone urls, defaults to the user of the provider")
+	cmd.PersistentFlags().IntP(string(cli.SshPort), "", 0, "port of ssh clone urls, defaults to the port of the provider")
+	cmd.PersistentFlags().StringP(string(cli.HomeDir), "", "", "dir repos of the host are cloned to, defaults to the scm homeDir")
+	cmd.PersistentFlags().BoolP(string(cli.AlwaysCreDir), "", false, "always clone repos of the host to their full directory hierarchy")
+	cmd.PersistentFlags().BoolP(string(cli.IncludeHost), "", false, "include the hostname in the directory hierarchy of clones")
+}
+
+// getHostFlagValue returns the value of a host flag with the type of the flag
+func getHostFlagValue(cmd *cobra.Command, flag cli.Flag) interface{} {
+	f := cmd.PersistentFlags().Lookup(string(flag))
+	switch f.Value.Type() {
+	case "bool":
+		v, err := strconv.ParseBool(f.Value.String())
+		cli.HandleFlagErr(err, flag)
+		return v
+	case "int":
+		v, err := strconv.Atoi(f.Value.String())
+		cli.HandleFlagErr(err, flag)
+		return v
+	default:
+		return f.Value.String()
+	}
+}
+
+// saveConfigFile writes the edits to the config file, edits leaving problems in the config are not saved
+func saveConfigFile(f *config.ConfigFile) {
+	if err := f.Save(); err != nil {
+		ui.GenericError("Change Not Saved", "The change would leave problems in the config", err)
+	}
+}
+
+// loadConfigFile returns the config file for editing
+func loadConfigFile() *config.ConfigFile {
+	gitrConfigPath, err := config.GetConfigPath()
+	if err != nil {
+		ui.ConfigError(err)
+	}
+	f, err := config.LoadConfigFile(gitrConfigPath)
+	if err != nil {
+		ui.ConfigError(err)
+	}
+	return f
+}
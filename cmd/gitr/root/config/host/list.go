@@ -0,0 +1,32 @@
+package host
+
+import (
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+var List = &cobra.Command{
+	Use:     "list",
+	Short:   "list the scm hosts of gitr config",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	Run:     listHandler,
+}
+
+func listHandler(cmd *cobra.Command, args []string) {
+	cfg, err := config.NewGitrConfig()
+	if err != nil {
+		ui.ConfigError(err)
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"hostname", "provider", "scheme", "default branch", "clone home dir", "create dir"})
+	for _, s := range cfg.Scm.Hosts {
+		t.AppendRow(table.Row{s.Hostname, s.Provider, s.Scheme, s.DefaultBranch, s.Clone.HomeDir, s.Clone.AlwaysCreDir})
+	}
+	t.Render()
+}
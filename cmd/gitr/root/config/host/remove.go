@@ -0,0 +1,25 @@
+package host
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+var Remove = &cobra.Command{
+	Use:     "remove <hostname>",
+	Short:   "remove an scm host from gitr config",
+	Aliases: []string{"rm"},
+	Args:    cobra.ExactArgs(1),
+	Run:     removeHandler,
+}
+
+func removeHandler(cmd *cobra.Command, args []string) {
+	f := loadConfigFile()
+	if err := f.RemoveHost(args[0]); err != nil {
+		ui.UnknownSCMHost(args[0])
+	}
+	saveConfigFile(f)
+	ui.Success("Host removed", fmt.Sprintf("%s was removed from %s", args[0], ui.Path(f.Path)))
+}
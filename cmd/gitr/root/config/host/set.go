@@ -0,0 +1,46 @@
+package host
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+var Set = &cobra.Command{
+	Use:   "set <hostname>",
+	Short: "set settings of an scm host of gitr config, only the flags passed are changed",
+	Example: `  gitr config host set gitlab.corp.net --scheme http
+  gitr config host set github.com --always-cre-dir=false --home-dir ~/work`,
+	Args: cobra.ExactArgs(1),
+	Run:  setHandler,
+}
+
+func init() {
+	addHostFlags(Set)
+}
+
+func setHandler(cmd *cobra.Command, args []string) {
+	f := loadConfigFile()
+	changed := 0
+	for _, k := range hostKeys {
+		if !cmd.PersistentFlags().Changed(string(k.flag)) {
+			continue
+		}
+		if err := f.SetHostValue(args[0], k.key, getHostFlagValue(cmd, k.flag)); err != nil {
+			var unknown *config.UnknownScmHostErr
+			if errors.As(err, &unknown) {
+				ui.UnknownSCMHost(args[0])
+			}
+			ui.GenericError("Failed to Set Host", fmt.Sprintf("Could not set %s of %s", k.key, args[0]), err)
+		}
+		changed++
+	}
+	if changed == 0 {
+		ui.Error("Nothing to Set", fmt.Sprintf("No settings of %s were passed.", args[0]), "Run "+ui.Cmd("gitr config host set --help")+" for the settings")
+	}
+	saveConfigFile(f)
+	ui.Success("Host updated", fmt.Sprintf("%d setting(s) of %s were changed in %s", changed, args[0], ui.Path(f.Path)))
+}
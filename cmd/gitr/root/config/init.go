@@ -16,5 +16,9 @@ func initHandler(cmd *cobra.Command, args []string) {
 	if err := config.EnsureInitialConfig(); err != nil {
 		ui.GenericError("Configuration Error", "Failed to initialize configuration", err)
 	}
-	ui.ConfigInitSuccess()
+	gitrConfigPath, err := config.GetConfigPath()
+	if err != nil {
+		ui.GenericError("Configuration Error", "Failed to determine the config file", err)
+	}
+	ui.ConfigInitSuccess(gitrConfigPath)
 }
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/internal/cli"
 	"github.com/swarupdonepudi/gitr/pkg/config"
 	"github.com/swarupdonepudi/gitr/pkg/ui"
 	"gopkg.in/yaml.v3"
@@ -12,15 +15,66 @@ import (
 var Show = &cobra.Command{
 	Use:   "show",
 	Short: "show gitr config",
-	Run:   showHandler,
+	Long: `show gitr config
+
+The config file is shown by default. With --effective the config used by gitr is shown: the config file merged
+with the nearest .gitr.yaml found by walking up from the current dir and with the GITR_* env vars, each taking
+precedence over the previous one. --origin shows the file and line, or the env var, each value came from.`,
+	Example: `  gitr config show
+  gitr config show --effective --origin`,
+	Run: showHandler,
+}
+
+func init() {
+	Show.PersistentFlags().BoolP(string(cli.Effective), "", false, "show the config merged with the workspace config file and the env vars")
+	Show.PersistentFlags().BoolP(string(cli.Origin), "", false, "show where each value of the effective config came from")
 }
 
 func showHandler(cmd *cobra.Command, args []string) {
-	cfg, err := config.NewGitrConfig()
+	effective, err := cmd.PersistentFlags().GetBool(string(cli.Effective))
+	cli.HandleFlagErr(err, cli.Effective)
+	origin, err := cmd.PersistentFlags().GetBool(string(cli.Origin))
+	cli.HandleFlagErr(err, cli.Origin)
+
+	if !effective && !origin {
+		cfg, gitrConfigPath, err := config.ReadConfigFile()
+		if err != nil {
+			ui.ConfigError(err)
+		}
+		fmt.Println()
+		ui.ConfigPath(gitrConfigPath)
+		printConfig(cfg)
+		return
+	}
+
+	e, err := config.NewEffectiveConfig()
 	if err != nil {
 		ui.ConfigError(err)
 	}
-	d, err := yaml.Marshal(&cfg)
+	fmt.Println()
+	for _, f := range e.Files {
+		ui.ConfigPath(f)
+	}
+	for _, env := range e.Env {
+		ui.ConfigPath("$" + env)
+	}
+	if !origin {
+		printConfig(e.Config)
+		return
+	}
+	fmt.Println()
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"key", "value", "origin"})
+	for _, entry := range e.Entries() {
+		t.AppendRow(table.Row{entry.Key, entry.Value, entry.Origin})
+	}
+	t.Render()
+	fmt.Println()
+}
+
+func printConfig(cfg *config.GitrConfig) {
+	d, err := yaml.Marshal(cfg)
 	if err != nil {
 		ui.GenericError("Configuration Error", "Failed to serialize configuration", err)
 	}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+var Validate = &cobra.Command{
+	Use:   "validate",
+	Short: "validate gitr config, reporting unknown keys and values, duplicate hosts and missing dirs",
+	Run:   validateHandler,
+}
+
+func validateHandler(cmd *cobra.Command, args []string) {
+	gitrConfigPath, err := config.GetConfigPath()
+	if err != nil {
+		ui.ConfigError(err)
+	}
+	if _, err := config.NewGitrConfig(); err != nil {
+		ui.ConfigError(err)
+	}
+	data, err := os.ReadFile(gitrConfigPath)
+	if err != nil {
+		ui.ConfigError(err)
+	}
+	if warnings := config.Warnings(data); len(warnings) > 0 {
+		ui.ConfigWarnings(gitrConfigPath, warnings)
+	}
+	ui.Success("Configuration valid", "No problems found in "+ui.Path(gitrConfigPath))
+}
@@ -8,9 +8,9 @@ import (
 	"github.com/swarupdonepudi/gitr/internal/cli"
 	"github.com/swarupdonepudi/gitr/pkg/config"
 	"github.com/swarupdonepudi/gitr/pkg/git"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
 	"github.com/swarupdonepudi/gitr/pkg/ui"
 	"github.com/swarupdonepudi/gitr/pkg/url"
-	"github.com/swarupdonepudi/gitr/pkg/web"
 )
 
 type WebCmdName string
@@ -111,17 +111,14 @@ func webHandler(cmd *cobra.Command, args []string) {
 		ui.ConfigError(err)
 	}
 
-	s, err := config.GetScmHost(cfg, url.GetHostname(remoteUrl))
-	if err != nil {
-		ui.UnknownSCMHost(url.GetHostname(remoteUrl))
-	}
+	s, prov, repoPath, webUrl := getRepoWebUrl(cfg, remoteUrl)
+	repoName := url.GetRepoName(repoPath)
 
-	repoPath, err := url.GetRepoPath(remoteUrl, s.Hostname, s.Provider)
-	if err != nil {
-		ui.GenericError("Failed to Parse Repository", "Could not parse repository path from URL", err)
+	// pull requests and issues of a fork are raised against the repo it was forked from
+	upstreamProv, upstreamWebUrl := prov, webUrl
+	if upstreamUrl, err := git.GetGitRemoteUrlByName(r, git.UpstreamRemoteName); err == nil {
+		_, upstreamProv, _, upstreamWebUrl = getRepoWebUrl(cfg, upstreamUrl)
 	}
-	repoName := url.GetRepoName(repoPath)
-	webUrl := web.GetWebUrl(s.Provider, s.Scheme, s.Hostname, repoPath)
 
 	if dry {
 		ui.WebInfo(string(s.Provider), s.Hostname, remoteUrl, webUrl, repoPath, repoName, branch)
@@ -130,19 +127,19 @@ func webHandler(cmd *cobra.Command, args []string) {
 
 	switch WebCmdName(cmd.Name()) {
 	case branches:
-		url.OpenInBrowser(web.GetBranchesUrl(s.Provider, webUrl))
+		openWebPage(prov.BranchesURL(webUrl))
 	case prs:
-		url.OpenInBrowser(web.GetPrsUrl(s.Provider, webUrl))
+		openWebPage(upstreamProv.PrsURL(upstreamWebUrl))
 	case commits:
-		url.OpenInBrowser(web.GetCommitsUrl(s.Provider, webUrl, branch))
+		openWebPage(prov.CommitsURL(webUrl, branch))
 	case issues:
-		url.OpenInBrowser(web.GetIssuesUrl(s.Provider, webUrl))
+		openWebPage(upstreamProv.IssuesURL(upstreamWebUrl))
 	case tags:
-		url.OpenInBrowser(web.GetTagsUrl(s.Provider, webUrl))
+		openWebPage(prov.TagsURL(webUrl))
 	case releases:
-		url.OpenInBrowser(web.GetReleasesUrl(s.Provider, webUrl))
+		openWebPage(prov.ReleasesURL(webUrl))
 	case pipelines:
-		url.OpenInBrowser(web.GetPipelinesUrl(s.Provider, webUrl))
+		openWebPage(prov.PipelinesURL(webUrl))
 	case webHome:
 		url.OpenInBrowser(webUrl)
 	case rem:
@@ -163,8 +160,34 @@ func webHandler(cmd *cobra.Command, args []string) {
 				branchToOpen = defaultBranch
 			}
 		}
-		url.OpenInBrowser(web.GetRemUrl(s.Provider, webUrl, branchToOpen))
+		openWebPage(prov.RemURL(webUrl, branchToOpen))
 	default:
 		ui.Error("Unknown Command", fmt.Sprintf("The command '%s' is not recognized.", cmd.Name()))
 	}
 }
+
+// openWebPage opens the web page in the browser, providers without the page return a *provider.NotSupportedErr
+func openWebPage(webPageUrl string, err error) {
+	if err != nil {
+		ui.FailedToOpenPage(err)
+	}
+	url.OpenInBrowser(webPageUrl)
+}
+
+// getRepoWebUrl returns the scm host, its provider, the repo path and the web url of the repo behind a remote url
+func getRepoWebUrl(cfg *config.GitrConfig, remoteUrl string) (*config.ScmHost, provider.Provider, string, string) {
+	s, err := config.GetScmHost(cfg, url.GetHostname(remoteUrl))
+	if err != nil {
+		ui.UnknownSCMHost(url.GetHostname(remoteUrl))
+	}
+
+	repoPath, err := url.GetRepoPath(remoteUrl, s.Hostname, s.Provider)
+	if err != nil {
+		ui.GenericError("Failed to Parse Repository", "Could not parse repository path from URL", err)
+	}
+	prov, err := provider.ForRepo(s, repoPath)
+	if err != nil {
+		ui.GenericError("Unknown Provider", fmt.Sprintf("The provider of %s is not supported", s.Hostname), err)
+	}
+	return s, prov, repoPath, prov.RepoWebURL(s.Scheme, s.Hostname, repoPath)
+}
@@ -5,10 +5,36 @@ import "github.com/swarupdonepudi/gitr/pkg/ui"
 type Flag string
 
 const (
-	Dry    Flag = "dry"
-	CreDir Flag = "create-dir"
-	Debug  Flag = "debug"
-	Token  Flag = "token"
+	Dry          Flag = "dry"
+	CreDir       Flag = "create-dir"
+	Debug        Flag = "debug"
+	Config       Flag = "config"
+	Token        Flag = "token"
+	File         Flag = "file"
+	Jobs         Flag = "jobs"
+	Archived     Flag = "archived"
+	Forks        Flag = "forks"
+	Match        Flag = "match"
+	Topic        Flag = "topic"
+	Depth        Flag = "depth"
+	Filter       Flag = "filter"
+	SingleBranch Flag = "single-branch"
+	Sparse       Flag = "sparse"
+	Fork         Flag = "fork"
+	ExistingDir  Flag = "on-existing-dir"
+	Submodules   Flag = "recurse-submodules"
+	Lfs          Flag = "lfs"
+	Provider     Flag = "provider"
+	Scheme       Flag = "scheme"
+	Branch       Flag = "default-branch"
+	ApiUrl       Flag = "api-url"
+	SshUser      Flag = "ssh-user"
+	SshPort      Flag = "ssh-port"
+	HomeDir      Flag = "home-dir"
+	AlwaysCreDir Flag = "always-cre-dir"
+	IncludeHost  Flag = "include-host-for-cre-dir"
+	Effective    Flag = "effective"
+	Origin       Flag = "origin"
 )
 
 func HandleFlagErr(err error, flag Flag) {
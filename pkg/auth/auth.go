@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/redact"
+	"github.com/swarupdonepudi/gitr/pkg/url"
+)
+
+// Transport is the protocol a credential is requested for
+type Transport string
+
+const (
+	TransportHttp Transport = "http"
+	TransportSsh  Transport = "ssh"
+)
+
+// Source identifies where a credential comes from
+type Source string
+
+const (
+	SourceFlag          Source = "flag"
+	SourceEnv           Source = "env"
+	SourceTokenFile     Source = "token-file"
+	SourceNetrc         Source = "netrc"
+	SourceGitCredential Source = "git-credential"
+	SourceSshAgent      Source = "ssh-agent"
+	SourceSshKey        Source = "ssh-key"
+)
+
+// DefaultOrder is the order in which credential sources are tried when a host does not configure one
+var DefaultOrder = []Source{
+	SourceFlag,
+	SourceEnv,
+	SourceTokenFile,
+	SourceNetrc,
+	SourceGitCredential,
+	SourceSshAgent,
+	SourceSshKey,
+}
+
+// Credential is an authentication method along with the source that supplied it
+type Credential struct {
+	Source Source
+	Method transport.AuthMethod
+}
+
+// AuthMethod returns the go-git auth method of the credential, or nil when there is no credential
+func (c *Credential) AuthMethod() transport.AuthMethod {
+	if c == nil {
+		return nil
+	}
+	return c.Method
+}
+
+// Token returns the password or token of http credentials and an empty string for any other credential
+func (c *Credential) Token() string {
+	if c == nil {
+		return ""
+	}
+	if basic, ok := c.Method.(*http.BasicAuth); ok {
+		return basic.Password
+	}
+	return ""
+}
+
+// Authenticator looks up a credential for a host in a single source.
+// A nil credential without an error means the source has nothing for the host.
+type Authenticator interface {
+	Source() Source
+	Authenticate(s *config.ScmHost, t Transport) (*Credential, error)
+}
+
+// Chain tries a list of authenticators in order and returns the first credential found.
+// The result of each transport is remembered so that sources such as git credential helpers run only once.
+type Chain struct {
+	host           *config.ScmHost
+	authenticators []Authenticator
+	resolved       map[Transport]*Credential
+}
+
+// NewChain creates the authenticator chain of the scm host using the auth order of the host.
+// token is the value of the --token flag and is ignored when empty.
+func NewChain(s *config.ScmHost, token string) (*Chain, error) {
+	order := DefaultOrder
+	if len(s.AuthOrder) > 0 {
+		order = make([]Source, 0, len(s.AuthOrder))
+		for _, name := range s.AuthOrder {
+			order = append(order, Source(name))
+		}
+	}
+	redact.Register(token)
+	c := &Chain{host: s, authenticators: make([]Authenticator, 0, len(order)), resolved: make(map[Transport]*Credential)}
+	for _, source := range order {
+		a, err := newAuthenticator(source, token)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid authOrder for %s", s.Hostname)
+		}
+		c.authenticators = append(c.authenticators, a)
+	}
+	return c, nil
+}
+
+func newAuthenticator(source Source, token string) (Authenticator, error) {
+	switch source {
+	case SourceFlag:
+		return &flagAuthenticator{token: strings.TrimSpace(token)}, nil
+	case SourceEnv:
+		return &envAuthenticator{}, nil
+	case SourceTokenFile:
+		return &tokenFileAuthenticator{}, nil
+	case SourceNetrc:
+		return &netrcAuthenticator{}, nil
+	case SourceGitCredential:
+		return &gitCredentialAuthenticator{}, nil
+	case SourceSshAgent:
+		return &sshAgentAuthenticator{}, nil
+	case SourceSshKey:
+		return &sshKeyAuthenticator{}, nil
+	}
+	return nil, errors.Errorf("unknown credential source %s", source)
+}
+
+// Hostname returns the host the chain supplies credentials for
+func (c *Chain) Hostname() string {
+	if c == nil {
+		return ""
+	}
+	return c.host.Hostname
+}
+
+// Authenticate returns the first credential found for the transport, or nil when no source has one.
+// Sources that fail are skipped so that a broken source does not hide the ones after it.
+func (c *Chain) Authenticate(t Transport) *Credential {
+	if c == nil || t == "" {
+		return nil
+	}
+	if cred, ok := c.resolved[t]; ok {
+		return cred
+	}
+	cred := c.lookup(t)
+	c.resolved[t] = cred
+	return cred
+}
+
+func (c *Chain) lookup(t Transport) *Credential {
+	for _, a := range c.authenticators {
+		cred, err := a.Authenticate(c.host, t)
+		if err != nil {
+			log.Debugf("skipping %s credentials for %s: %v", a.Source(), c.host.Hostname, err)
+			continue
+		}
+		if cred != nil {
+			redact.Register(cred.Token())
+			log.Debugf("using %s credentials for %s from %s", t, c.host.Hostname, cred.Source)
+			return cred
+		}
+	}
+	log.Debugf("no %s credentials found for %s", t, c.host.Hostname)
+	return nil
+}
+
+// TransportOf returns the transport used by a clone or remote url,
+// and an empty transport for urls that need no credentials such as local paths
+func TransportOf(repoUrl string) Transport {
+	switch {
+	case strings.HasPrefix(repoUrl, "http://"), strings.HasPrefix(repoUrl, "https://"):
+		return TransportHttp
+	case url.IsGitSshUrl(repoUrl):
+		return TransportSsh
+	}
+	return ""
+}
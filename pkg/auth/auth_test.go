@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+func TestParseNetrc(t *testing.T) {
+	content := `
+machine gitlab.com login jane password glpat-123
+machine github.com
+  login octocat
+  password ghp-456
+default login anonymous password guest
+`
+	var tests = []struct {
+		hostname string
+		login    string
+		password string
+	}{
+		{"github.com", "octocat", "ghp-456"},
+		{"gitlab.com", "jane", "glpat-123"},
+		{"bitbucket.org", "anonymous", "guest"},
+	}
+	t.Run("entry of the host should be used and default entry otherwise", func(t *testing.T) {
+		for _, tc := range tests {
+			login, password := parseNetrc(content, tc.hostname)
+			if login != tc.login || password != tc.password {
+				t.Errorf("expecting %s/%s for %s but got %s/%s", tc.login, tc.password, tc.hostname, login, password)
+			}
+		}
+	})
+}
+
+func TestHostTokenEnvVar(t *testing.T) {
+	if got := HostTokenEnvVar("git.example-corp.com"); got != "GITR_TOKEN_GIT_EXAMPLE_CORP_COM" {
+		t.Errorf("expecting GITR_TOKEN_GIT_EXAMPLE_CORP_COM but got %s", got)
+	}
+}
+
+func TestChain(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("NETRC", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("SSH_AUTH_SOCK", "")
+	tokenDir := filepath.Join(homeDir, ".personal_access_tokens")
+	if err := os.MkdirAll(tokenDir, os.ModePerm); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tokenDir, "github.com"), []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(homeDir, ".netrc"), []byte("machine github.com login jane password netrc-token"), 0600); err != nil {
+		t.Fatalf("failed to write netrc file: %v", err)
+	}
+	s := &config.ScmHost{Hostname: "github.com", Provider: config.GitHub, Scheme: config.Https}
+
+	var tests = []struct {
+		name      string
+		token     string
+		env       string
+		authOrder []string
+		source    Source
+		secret    string
+	}{
+		{"flag should win over every other source", "flag-token", "env-token", nil, SourceFlag, "flag-token"},
+		{"env should win over token file", "", "env-token", nil, SourceEnv, "env-token"},
+		{"token file should be used without flag and env", "", "", nil, SourceTokenFile, "file-token"},
+		{"configured order should be respected", "flag-token", "env-token", []string{"netrc", "flag"}, SourceNetrc, "netrc-token"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(HostTokenEnvVar(s.Hostname), tc.env)
+			host := *s
+			host.AuthOrder = tc.authOrder
+			chain, err := NewChain(&host, tc.token)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			cred := chain.Authenticate(TransportHttp)
+			if cred == nil || cred.Source != tc.source || cred.Token() != tc.secret {
+				t.Errorf("expecting %s credential from %s but got %+v", tc.secret, tc.source, cred)
+			}
+		})
+	}
+	t.Run("debug logs should report the source without the secret", func(t *testing.T) {
+		var buf bytes.Buffer
+		log.SetOutput(&buf)
+		log.SetLevel(log.DebugLevel)
+		defer func() {
+			log.SetOutput(os.Stderr)
+			log.SetLevel(log.InfoLevel)
+		}()
+		chain, err := NewChain(s, "flag-token")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		chain.Authenticate(TransportHttp)
+		if !strings.Contains(buf.String(), "from flag") || strings.Contains(buf.String(), "flag-token") {
+			t.Errorf("expecting source without secret in debug log but got %q", buf.String())
+		}
+	})
+	t.Run("http sources should not be used for ssh", func(t *testing.T) {
+		host := *s
+		host.AuthOrder = []string{"flag", "token-file", "ssh-agent"}
+		chain, err := NewChain(&host, "flag-token")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cred := chain.Authenticate(TransportSsh); cred != nil {
+			t.Errorf("expecting no ssh credential but got %+v", cred)
+		}
+	})
+	t.Run("unknown source should be an error", func(t *testing.T) {
+		host := *s
+		host.AuthOrder = []string{"keychain"}
+		if _, err := NewChain(&host, ""); err == nil {
+			t.Errorf("expecting an error")
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	ssh2 "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/leftbin/go-util/pkg/file"
+	"github.com/pkg/errors"
+	intssh "github.com/swarupdonepudi/gitr/internal/ssh"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// tokenUsername is the username sent along with access tokens, scm hosts accept any non empty username
+const tokenUsername = "x-access-token"
+
+// sshUsername returns the user of ssh clone urls of the host
+func sshUsername(s *config.ScmHost) string {
+	if s != nil && s.SshUser != "" {
+		return s.SshUser
+	}
+	return config.DefaultSshUser
+}
+
+func tokenCredential(source Source, token string) *Credential {
+	return &Credential{Source: source, Method: &http.BasicAuth{Username: tokenUsername, Password: token}}
+}
+
+// flagAuthenticator supplies the token passed with the --token flag
+type flagAuthenticator struct {
+	token string
+}
+
+func (a *flagAuthenticator) Source() Source {
+	return SourceFlag
+}
+
+func (a *flagAuthenticator) Authenticate(s *config.ScmHost, t Transport) (*Credential, error) {
+	if t != TransportHttp || a.token == "" {
+		return nil, nil
+	}
+	return tokenCredential(SourceFlag, a.token), nil
+}
+
+// envAuthenticator supplies tokens from GITR_TOKEN_<HOST> and the well known env vars of the provider
+type envAuthenticator struct{}
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// HostTokenEnvVar returns the name of the env var holding the token of a host, ex: GITR_TOKEN_GITHUB_COM
+func HostTokenEnvVar(hostname string) string {
+	return "GITR_TOKEN_" + nonAlphanumeric.ReplaceAllString(strings.ToUpper(hostname), "_")
+}
+
+func (a *envAuthenticator) Source() Source {
+	return SourceEnv
+}
+
+func (a *envAuthenticator) Authenticate(s *config.ScmHost, t Transport) (*Credential, error) {
+	if t != TransportHttp {
+		return nil, nil
+	}
+	envVars := []string{HostTokenEnvVar(s.Hostname)}
+	switch s.Provider {
+	case config.GitHub:
+		envVars = append(envVars, "GITHUB_TOKEN", "GH_TOKEN")
+	case config.GitLab:
+		envVars = append(envVars, "GITLAB_TOKEN")
+	case config.Gitea:
+		envVars = append(envVars, "GITEA_TOKEN")
+	case config.AzureDevOps:
+		envVars = append(envVars, "AZURE_DEVOPS_EXT_PAT")
+	}
+	for _, envVar := range envVars {
+		if token := strings.TrimSpace(os.Getenv(envVar)); token != "" {
+			return tokenCredential(SourceEnv, token), nil
+		}
+	}
+	return nil, nil
+}
+
+// tokenFileAuthenticator supplies the token stored in ~/.personal_access_tokens/<hostname>
+type tokenFileAuthenticator struct{}
+
+func (a *tokenFileAuthenticator) Source() Source {
+	return SourceTokenFile
+}
+
+func (a *tokenFileAuthenticator) Authenticate(s *config.ScmHost, t Transport) (*Credential, error) {
+	if t != TransportHttp {
+		return nil, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user home dir")
+	}
+	tokenFilePath := filepath.Join(homeDir, ".personal_access_tokens", s.Hostname)
+	if !file.IsFileExists(tokenFilePath) {
+		return nil, nil
+	}
+	token, err := os.ReadFile(tokenFilePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s file", tokenFilePath)
+	}
+	if strings.TrimSpace(string(token)) == "" {
+		return nil, nil
+	}
+	return tokenCredential(SourceTokenFile, strings.TrimSpace(string(token))), nil
+}
+
+// netrcAuthenticator supplies the login and password of the host from $NETRC or ~/.netrc
+type netrcAuthenticator struct{}
+
+func (a *netrcAuthenticator) Source() Source {
+	return SourceNetrc
+}
+
+func (a *netrcAuthenticator) Authenticate(s *config.ScmHost, t Transport) (*Credential, error) {
+	if t != TransportHttp {
+		return nil, nil
+	}
+	netrcPath := os.Getenv("NETRC")
+	if netrcPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get user home dir")
+		}
+		netrcPath = filepath.Join(homeDir, ".netrc")
+	}
+	if !file.IsFileExists(netrcPath) {
+		return nil, nil
+	}
+	content, err := os.ReadFile(netrcPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s file", netrcPath)
+	}
+	login, password := parseNetrc(string(content), s.Hostname)
+	if password == "" {
+		return nil, nil
+	}
+	if login == "" {
+		login = tokenUsername
+	}
+	return &Credential{Source: SourceNetrc, Method: &http.BasicAuth{Username: login, Password: password}}, nil
+}
+
+type netrcEntry struct {
+	machine   string
+	login     string
+	password  string
+	isDefault bool
+}
+
+// parseNetrc returns the login and password of the machine entry of the hostname,
+// falling back to the default entry when there is no entry for the hostname
+func parseNetrc(content, hostname string) (login, password string) {
+	entries := make([]*netrcEntry, 0)
+	var current *netrcEntry
+	fields := strings.Fields(content)
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			current = &netrcEntry{}
+			entries = append(entries, current)
+			if i+1 < len(fields) {
+				i++
+				current.machine = fields[i]
+			}
+		case "default":
+			current = &netrcEntry{isDefault: true}
+			entries = append(entries, current)
+		case "login", "password", "account":
+			if current == nil || i+1 >= len(fields) {
+				continue
+			}
+			i++
+			switch fields[i-1] {
+			case "login":
+				current.login = fields[i]
+			case "password":
+				current.password = fields[i]
+			}
+		case "macdef":
+			// macro definitions can not be told apart from tokens without tracking lines, nothing after them is parsed
+			i = len(fields)
+		}
+	}
+	var fallback *netrcEntry
+	for _, e := range entries {
+		if e.isDefault {
+			if fallback == nil {
+				fallback = e
+			}
+			continue
+		}
+		if e.machine == hostname {
+			return e.login, e.password
+		}
+	}
+	if fallback != nil {
+		return fallback.login, fallback.password
+	}
+	return "", ""
+}
+
+// gitCredentialAuthenticator asks the credential helpers configured in git with `git credential fill`
+type gitCredentialAuthenticator struct{}
+
+func (a *gitCredentialAuthenticator) Source() Source {
+	return SourceGitCredential
+}
+
+func (a *gitCredentialAuthenticator) Authenticate(s *config.ScmHost, t Transport) (*Credential, error) {
+	if t != TransportHttp {
+		return nil, nil
+	}
+	scheme := s.Scheme
+	if scheme == "" {
+		scheme = config.Https
+	}
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=%s\nhost=%s\n\n", scheme, s.Hostname))
+	// never let git or a helper fall back to prompting on the terminal
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		// git exits with an error when no helper has credentials and prompting is disabled
+		return nil, nil
+	}
+	var username, password string
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "username":
+			username = value
+		case "password":
+			password = value
+		}
+	}
+	if password == "" {
+		return nil, nil
+	}
+	if username == "" {
+		username = tokenUsername
+	}
+	return &Credential{Source: SourceGitCredential, Method: &http.BasicAuth{Username: username, Password: password}}, nil
+}
+
+// sshAgentAuthenticator uses the keys of the running ssh-agent
+type sshAgentAuthenticator struct{}
+
+func (a *sshAgentAuthenticator) Source() Source {
+	return SourceSshAgent
+}
+
+func (a *sshAgentAuthenticator) Authenticate(s *config.ScmHost, t Transport) (*Credential, error) {
+	if t != TransportSsh || os.Getenv("SSH_AUTH_SOCK") == "" {
+		return nil, nil
+	}
+	method, err := ssh2.NewSSHAgentAuth(sshUsername(s))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to ssh-agent")
+	}
+	return &Credential{Source: SourceSshAgent, Method: method}, nil
+}
+
+// sshKeyAuthenticator uses the identity file of the host in ~/.ssh/config, or ~/.ssh/id_rsa
+type sshKeyAuthenticator struct{}
+
+func (a *sshKeyAuthenticator) Source() Source {
+	return SourceSshKey
+}
+
+func (a *sshKeyAuthenticator) Authenticate(s *config.ScmHost, t Transport) (*Credential, error) {
+	if t != TransportSsh {
+		return nil, nil
+	}
+	sshKeyPath, err := intssh.GetKeyPath(s.Hostname)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get ssh key path")
+	}
+	if !file.IsFileExists(sshKeyPath) {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(sshKeyPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s file", sshKeyPath)
+	}
+	signer, err := ssh.ParsePrivateKey(pem)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse private key %s", sshKeyPath)
+	}
+	return &Credential{Source: SourceSshKey, Method: &ssh2.PublicKeys{User: sshUsername(s), Signer: signer}}, nil
+}
@@ -0,0 +1,87 @@
+package clone
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	"github.com/swarupdonepudi/gitr/pkg/clonerr"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+// ErrGitNotFound is returned when the exec backend is configured and there is no git binary on the PATH
+var ErrGitNotFound = errors.New("git binary not found on PATH")
+
+// Backend clones a repository into a directory.
+// Implementations feed the clone progress display and return a *BackendError when the clone fails.
+type Backend interface {
+	Name() config.CloneBackend
+	Clone(repoUrl, clonePath string, chain *auth.Chain, opts *Options) error
+	// UpdateSubmodules initializes and checks out the submodules of a clone, including nested submodules
+	UpdateSubmodules(repoLocation string, chain *auth.Chain) error
+	// Fetch fetches the refspecs from the remote of a clone, no deeper than depth when it is set
+	Fetch(repoLocation, remoteName string, refSpecs []string, depth int, chain *auth.Chain) error
+	// Checkout checks out the first candidate that names a branch or a tag of a clone and returns its name
+	Checkout(repoLocation string, candidates []string, chain *auth.Chain) (string, error)
+	// FastForward moves the checked out branch of a clone forward to the commit and updates the worktree
+	FastForward(repoLocation string, commit plumbing.Hash, chain *auth.Chain) error
+}
+
+// Classified clone errors, use errors.Is to check for them
+var (
+	ErrRepoNotFound    = clonerr.ErrRepoNotFound
+	ErrAuthFailed      = clonerr.ErrAuthFailed
+	ErrHostUnreachable = clonerr.ErrHostUnreachable
+	ErrHostKeyMismatch = clonerr.ErrHostKeyMismatch
+	ErrDiskFull        = clonerr.ErrDiskFull
+)
+
+// BackendError is the error of a failed clone, holding the output of the backend when there is one.
+// Kind is one of the classified clone errors, or nil when the cause of the failure is unknown.
+type BackendError struct {
+	Backend config.CloneBackend
+	Url     string
+	Output  string
+	Kind    error
+	Err     error
+}
+
+func (e *BackendError) Error() string {
+	if output := strings.TrimSpace(e.Output); output != "" {
+		return fmt.Sprintf("%s clone of %s failed: %s", e.Backend, e.Url, output)
+	}
+	return fmt.Sprintf("%s clone of %s failed: %v", e.Backend, e.Url, e.Err)
+}
+
+func (e *BackendError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// NewBackend returns the clone backend with the name.
+// auto picks the exec backend when git is installed and the gogit backend otherwise.
+func NewBackend(name config.CloneBackend) (Backend, error) {
+	switch name {
+	case config.CloneBackendExec:
+		if _, err := exec.LookPath("git"); err != nil {
+			return nil, ErrGitNotFound
+		}
+		return &execBackend{}, nil
+	case config.CloneBackendGoGit:
+		return &goGitBackend{}, nil
+	case "", config.CloneBackendAuto:
+		if _, err := exec.LookPath("git"); err != nil {
+			log.Debugf("git binary not found, cloning with go-git")
+			return &goGitBackend{}, nil
+		}
+		return &execBackend{}, nil
+	}
+	return nil, errors.Errorf("unknown clone backend %s, expecting one of %s, %s or %s", name,
+		config.CloneBackendExec, config.CloneBackendGoGit, config.CloneBackendAuto)
+}
@@ -0,0 +1,123 @@
+package clone
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+func TestBackendCloneShallowSparse(t *testing.T) {
+	ui.SetQuiet(true)
+	defer ui.SetQuiet(false)
+	src := "file://" + newTestRepo(t, map[string]string{"README.md": "readme", "docs/guide.md": "guide", "pkg/main.go": "package main"}, 3)
+
+	backends := []Backend{&goGitBackend{}}
+	if _, err := exec.LookPath("git"); err == nil {
+		backends = append(backends, &execBackend{})
+	}
+	for _, backend := range backends {
+		clonePath := filepath.Join(t.TempDir(), "repo")
+		if err := backend.Clone(src, clonePath, nil, &Options{Depth: 1, SparsePaths: []string{"docs"}}); err != nil {
+			t.Fatalf("unexpected %s error: %v", backend.Name(), err)
+		}
+		t.Run(string(backend.Name())+" should check out only the sparse paths", func(t *testing.T) {
+			if _, err := os.Stat(filepath.Join(clonePath, "docs", "guide.md")); err != nil {
+				t.Errorf("expecting docs/guide.md to be checked out: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(clonePath, "pkg", "main.go")); !os.IsNotExist(err) {
+				t.Errorf("expecting pkg/main.go not to be checked out")
+			}
+		})
+		t.Run(string(backend.Name())+" should truncate history to the depth", func(t *testing.T) {
+			r, err := git.PlainOpen(clonePath)
+			if err != nil {
+				t.Fatalf("failed to open clone: %v", err)
+			}
+			commits, err := r.Log(&git.LogOptions{})
+			if err != nil {
+				t.Fatalf("failed to read log: %v", err)
+			}
+			count := 0
+			_ = commits.ForEach(func(c *object.Commit) error {
+				count++
+				return nil
+			})
+			if count != 1 {
+				t.Errorf("expecting 1 commit but got %d", count)
+			}
+		})
+		t.Run(string(backend.Name())+" should return a repo not found error for missing repos", func(t *testing.T) {
+			err := backend.Clone("file://"+filepath.Join(t.TempDir(), "missing"), filepath.Join(t.TempDir(), "repo"), nil, &Options{})
+			var backendErr *BackendError
+			if !errors.As(err, &backendErr) || backendErr.Backend != backend.Name() {
+				t.Errorf("expecting %s backend error but got %v", backend.Name(), err)
+			}
+			if !errors.Is(err, ErrRepoNotFound) {
+				t.Errorf("expecting repo not found error but got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	t.Run("gogit backend should not need git", func(t *testing.T) {
+		backend, err := NewBackend(config.CloneBackendGoGit)
+		if err != nil || backend.Name() != config.CloneBackendGoGit {
+			t.Errorf("expecting gogit backend but got %v, %v", backend, err)
+		}
+	})
+	t.Run("auto should fall back to gogit without git", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		backend, err := NewBackend(config.CloneBackendAuto)
+		if err != nil || backend.Name() != config.CloneBackendGoGit {
+			t.Errorf("expecting gogit backend but got %v, %v", backend, err)
+		}
+		if _, err := NewBackend(config.CloneBackendExec); !errors.Is(err, ErrGitNotFound) {
+			t.Errorf("expecting git not found error but got %v", err)
+		}
+	})
+	t.Run("unknown backend should be an error", func(t *testing.T) {
+		if _, err := NewBackend("libgit2"); err == nil {
+			t.Errorf("expecting an error")
+		}
+	})
+}
+
+func TestGitAuthEnv(t *testing.T) {
+	t.Setenv("GIT_CONFIG_COUNT", "")
+	cred := &auth.Credential{Source: auth.SourceFlag, Method: &http.BasicAuth{Username: "x-access-token", Password: "secret"}}
+	env := gitAuthEnv("https://github.com/owner/repo.git", cred)
+	t.Run("credential should be scoped to the host of the url", func(t *testing.T) {
+		expected := []string{
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.https://github.com/.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:secret")),
+		}
+		if strings.Join(env, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("expecting %v but got %v", expected, env)
+		}
+	})
+	t.Run("config entries exported by the user should be kept", func(t *testing.T) {
+		t.Setenv("GIT_CONFIG_COUNT", "2")
+		env := gitAuthEnv("https://github.com/owner/repo.git", cred)
+		if env[0] != "GIT_CONFIG_COUNT=3" || !strings.HasPrefix(env[1], "GIT_CONFIG_KEY_2=") || !strings.HasPrefix(env[2], "GIT_CONFIG_VALUE_2=") {
+			t.Errorf("expecting the header to be added as the third entry but got %v", env)
+		}
+	})
+	t.Run("no env should be set without credential", func(t *testing.T) {
+		if env := gitAuthEnv("https://github.com/owner/repo.git", nil); len(env) != 0 {
+			t.Errorf("expecting no env but got %v", env)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package clone
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/leftbin/go-util/pkg/file"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/redact"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+// DefaultJobs is the number of repositories cloned concurrently when no limit is provided
+const DefaultJobs = 4
+
+// BulkStatus is the outcome of cloning a single repository as part of a bulk clone
+type BulkStatus string
+
+const (
+	BulkCloned         BulkStatus = "cloned"
+	BulkAlreadyPresent BulkStatus = "already-present"
+	BulkFailed         BulkStatus = "failed"
+	// BulkDuplicate is the status of urls that resolve to the clone path of an earlier url of the list
+	BulkDuplicate BulkStatus = "duplicate"
+)
+
+// BulkResult holds the outcome of cloning one entry of a bulk clone
+type BulkResult struct {
+	InputUrl  string
+	ClonePath string
+	Status    BulkStatus
+	Err       error
+	// Warnings are the warnings raised while cloning the repository
+	Warnings []Warning
+}
+
+// Warning is a warning raised while cloning a repository of a bulk clone
+type Warning struct {
+	Title   string
+	Message string
+}
+
+// ReadUrlList reads repository urls from r, one per line.
+// Blank lines and lines starting with # are ignored and duplicate urls are dropped.
+func ReadUrlList(r io.Reader) ([]string, error) {
+	urls := make([]string, 0)
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read repo urls")
+	}
+	return urls, nil
+}
+
+// CloneAll clones every url in inputUrls using a pool of jobs workers.
+// Urls that resolve to the same clone path, like the https and ssh urls of a repo, are cloned once.
+// Failures do not stop the remaining clones; results are returned in the order of inputUrls.
+func CloneAll(cfg *config.GitrConfig, inputUrls []string, opts *Options, jobs int) []*BulkResult {
+	if jobs < 1 {
+		jobs = 1
+	}
+	results := make([]*BulkResult, len(inputUrls))
+	pending := make([]*BulkResult, 0, len(inputUrls))
+	seen := make(map[string]bool)
+	for i, inputUrl := range inputUrls {
+		result := &BulkResult{InputUrl: inputUrl}
+		results[i] = result
+		clonePath, err := GetClonePath(cfg, inputUrl, opts.CreDir)
+		if err != nil {
+			result.Status = BulkFailed
+			result.Err = err
+			continue
+		}
+		result.ClonePath = clonePath
+		if seen[clonePath] {
+			result.Status = BulkDuplicate
+			continue
+		}
+		seen[clonePath] = true
+		pending = append(pending, result)
+	}
+
+	work := make(chan *BulkResult)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range work {
+				cloneOne(cfg, result, opts)
+			}
+		}()
+	}
+	for _, result := range pending {
+		work <- result
+	}
+	close(work)
+	wg.Wait()
+	return results
+}
+
+func cloneOne(cfg *config.GitrConfig, result *BulkResult, opts *Options) {
+	if file.IsDirExists(filepath.Join(result.ClonePath, ".git")) {
+		result.Status = BulkAlreadyPresent
+		return
+	}
+	log.Debugf("cloning %s to %s", result.InputUrl, result.ClonePath)
+	repoOpts := *opts
+	repoOpts.warnings = &result.Warnings
+	if _, err := Clone(cfg, result.InputUrl, &repoOpts); err != nil {
+		result.Status = BulkFailed
+		result.Err = err
+		return
+	}
+	result.Status = BulkCloned
+}
+
+// CountBulkResults returns the number of results with the given status
+func CountBulkResults(results []*BulkResult, status BulkStatus) int {
+	count := 0
+	for _, r := range results {
+		if r.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// PrintBulkCloneSummary renders a table with the outcome of every entry of a bulk clone,
+// followed by the warnings raised while cloning
+func PrintBulkCloneSummary(results []*BulkResult) {
+	println("")
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"status", "repo", "path / error"})
+	for _, r := range results {
+		detail := r.ClonePath
+		if r.Err != nil {
+			detail = redact.String(r.Err.Error())
+		}
+		t.AppendRow(table.Row{r.Status, r.InputUrl, detail})
+	}
+	total := fmt.Sprintf("%d cloned, %d already present, %d failed",
+		CountBulkResults(results, BulkCloned),
+		CountBulkResults(results, BulkAlreadyPresent),
+		CountBulkResults(results, BulkFailed))
+	if duplicates := CountBulkResults(results, BulkDuplicate); duplicates > 0 {
+		total = fmt.Sprintf("%s, %d duplicate", total, duplicates)
+	}
+	t.AppendFooter(table.Row{"", "total", total})
+	t.Render()
+	println("")
+	for _, r := range results {
+		for _, w := range r.Warnings {
+			ui.Warn(fmt.Sprintf("%s: %s", r.InputUrl, w.Title), w.Message)
+		}
+	}
+}
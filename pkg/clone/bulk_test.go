@@ -0,0 +1,96 @@
+package clone
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+func TestReadUrlList(t *testing.T) {
+	input := `
+# team repos
+https://github.com/owner/repo-a
+   https://github.com/owner/repo-b
+
+git@github.com:owner/repo-c.git
+https://github.com/owner/repo-a
+`
+	expected := []string{
+		"https://github.com/owner/repo-a",
+		"https://github.com/owner/repo-b",
+		"git@github.com:owner/repo-c.git",
+	}
+	t.Run("comments, blank lines and duplicates should be skipped", func(t *testing.T) {
+		urls, err := ReadUrlList(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(urls) != len(expected) {
+			t.Fatalf("expecting %d urls but got %d: %v", len(expected), len(urls), urls)
+		}
+		for i := range expected {
+			if urls[i] != expected[i] {
+				t.Errorf("expecting %s but got %s", expected[i], urls[i])
+			}
+		}
+	})
+}
+
+func TestCloneAll(t *testing.T) {
+	scmHome := t.TempDir()
+	cfg := &config.GitrConfig{Scm: &config.Scm{HomeDir: scmHome, Hosts: []*config.ScmHost{{Hostname: "github.com", Provider: config.GitHub, Clone: &config.CloneConfig{
+		AlwaysCreDir:         true,
+		IncludeHostForCreDir: true,
+	}}}}}
+	for _, repo := range []string{"repo-a", "repo-b"} {
+		if err := os.MkdirAll(filepath.Join(scmHome, "github.com", "owner", repo, ".git"), os.ModePerm); err != nil {
+			t.Fatalf("failed to set up existing repo: %v", err)
+		}
+	}
+	inputUrls := []string{
+		"https://github.com/owner/repo-a",
+		"https://unknown.example.com/owner/repo-x",
+		"https://github.com/owner/repo-b",
+	}
+
+	t.Run("existing repos should be reported as already present and unknown hosts as failed", func(t *testing.T) {
+		results := CloneAll(cfg, inputUrls, &Options{}, 2)
+		if len(results) != len(inputUrls) {
+			t.Fatalf("expecting %d results but got %d", len(inputUrls), len(results))
+		}
+		expected := []BulkStatus{BulkAlreadyPresent, BulkFailed, BulkAlreadyPresent}
+		for i, r := range results {
+			if r.InputUrl != inputUrls[i] {
+				t.Errorf("expecting result %d for %s but got %s", i, inputUrls[i], r.InputUrl)
+			}
+			if r.Status != expected[i] {
+				t.Errorf("expecting %s status for %s but got %s", expected[i], r.InputUrl, r.Status)
+			}
+		}
+		if r := results[1]; r.Err == nil {
+			t.Errorf("expecting an error for %s", r.InputUrl)
+		}
+		if got := CountBulkResults(results, BulkAlreadyPresent); got != 2 {
+			t.Errorf("expecting 2 already present repos but got %d", got)
+		}
+	})
+
+	t.Run("urls resolving to the same clone path should be cloned once", func(t *testing.T) {
+		results := CloneAll(cfg, []string{
+			"https://github.com/owner/repo-a",
+			"git@github.com:owner/repo-a.git",
+		}, &Options{}, 2)
+		if results[0].Status != BulkAlreadyPresent {
+			t.Errorf("expecting %s status for %s but got %s", BulkAlreadyPresent, results[0].InputUrl, results[0].Status)
+		}
+		if results[1].Status != BulkDuplicate {
+			t.Errorf("expecting %s status for %s but got %s", BulkDuplicate, results[1].InputUrl, results[1].Status)
+		}
+		if results[1].ClonePath != results[0].ClonePath {
+			t.Errorf("expecting clone path %s but got %s", results[0].ClonePath, results[1].ClonePath)
+		}
+	})
+}
@@ -2,134 +2,231 @@ package clone
 
 import (
 	"fmt"
-	"io"
-	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
-	ssh2 "github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/leftbin/go-util/pkg/file"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-	intssh "github.com/swarupdonepudi/gitr/internal/ssh"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
 	"github.com/swarupdonepudi/gitr/pkg/config"
 	"github.com/swarupdonepudi/gitr/pkg/ui"
 	"github.com/swarupdonepudi/gitr/pkg/url"
-	"golang.org/x/crypto/ssh"
 )
 
-func Clone(cfg *config.GitrConfig, inputUrl string, token string, creDir, dry bool) (repoLocation string, err error) {
+// Option names a clone option that falls back to the clone config of the scm host
+type Option string
+
+const (
+	OptionDepth         Option = "depth"
+	OptionFilter        Option = "filter"
+	OptionSingleBranch  Option = "singleBranch"
+	OptionSparsePaths   Option = "sparsePaths"
+	OptionOnExistingDir Option = "onExistingDir"
+	OptionSubmodules    Option = "submodules"
+	OptionLfs           Option = "lfs"
+)
+
+// Options holds the settings of a single clone invocation.
+// Depth, Filter, SingleBranch, SparsePaths, OnExistingDir, Submodules and Lfs fall back to the clone config of the scm host when not set.
+type Options struct {
+	Token        string
+	CreDir       bool
+	Dry          bool
+	Depth        int
+	Filter       string
+	SingleBranch bool
+	SparsePaths  []string
+	// OnExistingDir decides what happens when the clone path is a directory that is not a git repo
+	OnExistingDir config.ExistingDirPolicy
+	// Submodules decides whether submodules are cloned, and where
+	Submodules config.SubmoduleMode
+	// Lfs decides whether git lfs objects are downloaded after the clone
+	Lfs config.LfsMode
+	// Explicit holds the options that were set for the invocation, which keep their value even when it is a zero value,
+	// so that --depth=0 or --single-branch=false override the clone config of the host
+	Explicit map[Option]bool
+	// warnings collects the warnings of a clone that is part of a bulk clone,
+	// to show them in the summary instead of between the output of other clones
+	warnings *[]Warning
+}
+
+// warn shows a warning, or records it for the summary when the clone is part of a bulk clone
+func (o *Options) warn(title, message string) {
+	if o.warnings != nil {
+		*o.warnings = append(*o.warnings, Warning{Title: title, Message: message})
+		return
+	}
+	ui.Warn(title, message)
+}
+
+// withHostDefaults returns a copy of the options with unset values taken from the clone config of the host.
+// Options without an explicit value are unset when they hold the zero value.
+func (o *Options) withHostDefaults(c *config.CloneConfig) *Options {
+	opts := *o
+	if o.unset(OptionDepth, opts.Depth == 0) {
+		opts.Depth = c.Depth
+	}
+	if o.unset(OptionFilter, opts.Filter == "") {
+		opts.Filter = c.Filter
+	}
+	if o.unset(OptionSingleBranch, !opts.SingleBranch) {
+		opts.SingleBranch = c.SingleBranch
+	}
+	if o.unset(OptionSparsePaths, len(opts.SparsePaths) == 0) {
+		opts.SparsePaths = c.SparsePaths
+	}
+	if o.unset(OptionOnExistingDir, opts.OnExistingDir == "") {
+		opts.OnExistingDir = c.OnExistingDir
+	}
+	if o.unset(OptionSubmodules, opts.Submodules == "") {
+		opts.Submodules = c.Submodules
+	}
+	if o.unset(OptionLfs, opts.Lfs == "") {
+		opts.Lfs = c.Lfs
+	}
+	return &opts
+}
+
+func (o *Options) unset(option Option, isZero bool) bool {
+	return !o.Explicit[option] && isZero
+}
+
+func Clone(cfg *config.GitrConfig, inputUrl string, opts *Options) (repoLocation string, err error) {
 	// Strip query parameters and fragments from URLs (handles browser URLs with tracking params like ?utm_source=...)
 	inputUrl = url.StripQueryParams(inputUrl)
+	inputUrl, err = url.ExpandShorthand(cfg, inputUrl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve repo reference")
+	}
 
 	s, err := config.GetScmHost(cfg, url.GetHostname(inputUrl))
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to clone git repo with %s url", inputUrl)
 	}
+	opts = opts.withHostDefaults(s.Clone)
 	repoPath, err := url.GetRepoPath(inputUrl, s.Hostname, s.Provider)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get repo path")
 	}
-	repoLocation, err = GetClonePath(cfg, inputUrl, creDir)
+	repoLocation, err = GetClonePath(cfg, inputUrl, opts.CreDir)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get clone path")
 	}
-	if dry {
-		err := printGitrCloneInfo(cfg, inputUrl, creDir || s.Clone.AlwaysCreDir)
+	if opts.Dry {
+		err := printGitrCloneInfo(cfg, inputUrl, opts)
 		if err != nil {
 			return "", errors.Wrap(err, "failed to print gitr clone info")
 		}
 		return repoLocation, nil
 	}
-	if file.IsDirExists(repoLocation) {
-		if file.IsDirExists(filepath.Join(repoLocation, ".git")) {
+	ref, err := url.GetRef(inputUrl, s.Hostname, s.Provider)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get ref from url")
+	}
+	if file.IsDirExists(filepath.Join(repoLocation, ".git")) {
+		if ref == nil {
 			ui.RepoAlreadyExists(repoLocation)
 			return repoLocation, nil
 		}
-		if err := os.RemoveAll(repoLocation); err != nil {
-			return "", errors.Wrapf(err, "failed to remove %s dir", repoLocation)
+		chain, err := auth.NewChain(s, opts.Token)
+		if err != nil {
+			return "", err
+		}
+		backend, err := NewBackend(s.Clone.Backend)
+		if err != nil {
+			return "", err
+		}
+		return repoLocation, switchExistingCheckout(backend, repoLocation, ref, chain)
+	}
+	chain, err := auth.NewChain(s, opts.Token)
+	if err != nil {
+		return "", err
+	}
+	backend, err := NewBackend(s.Clone.Backend)
+	if err != nil {
+		return "", err
+	}
+	replacedPath, err := prepareCloneDir(repoLocation, opts.OnExistingDir)
+	if err != nil {
+		return repoLocation, err
+	}
+	createdDir := firstMissingDir(repoLocation)
+	clonedLocation, err := cloneRepo(s, backend, chain, inputUrl, repoPath, repoLocation, opts)
+	if err != nil {
+		cleanUpFailedClone(repoLocation, createdDir)
+		restoreReplacedDir(replacedPath, repoLocation, opts)
+		return "", err
+	}
+	removeReplacedDir(replacedPath, opts)
+	repoLocation = clonedLocation
+	if ref != nil {
+		if err := checkoutRef(backend, repoLocation, ref, opts, chain); err != nil {
+			return repoLocation, err
 		}
 	}
-	if url.IsGitUrl(inputUrl) {
+	if err := updateSubmodules(cfg, backend, chain, repoLocation, opts); err != nil {
+		return repoLocation, err
+	}
+	return repoLocation, pullLfsObjects(repoLocation, chain, opts)
+}
+
+// cloneRepo clones the repo to repoLocation using the clone url when one is provided,
+// and otherwise tries an ssh clone followed by an http clone.
+// Ssh urls are clone urls even without the .git suffix, as used by azure devops and gerrit.
+func cloneRepo(s *config.ScmHost, backend Backend, chain *auth.Chain, inputUrl, repoPath, repoLocation string, opts *Options) (string, error) {
+	if url.IsGitUrl(inputUrl) || url.IsGitSshUrl(inputUrl) {
 		if url.IsGitSshUrl(inputUrl) {
 			ui.Cloning(inputUrl)
-			if err := sshClone(inputUrl, repoLocation); err != nil {
+			if err := backend.Clone(inputUrl, repoLocation, chain, opts); err != nil {
 				return "", errors.Wrap(err, "error cloning the repo")
 			}
 			return repoLocation, nil
 		}
-		if token == "" {
-			token, err = getHttpsCloneToken(s.Hostname)
-			if err != nil {
-				return "", errors.Wrap(err, "failed to check if https clone token is configured")
-			}
-		}
-		if token != "" {
-			if err := httpsGitClone(inputUrl, token, repoLocation); err != nil {
+		if chain.Authenticate(auth.TransportHttp) != nil {
+			if err := backend.Clone(inputUrl, repoLocation, chain, opts); err != nil {
 				return "", errors.Wrap(err, "error cloning the repo")
 			}
 			return repoLocation, nil
 		}
 
 	}
-	if s.Provider == config.BitBucketDatacenter || s.Provider == config.BitBucketCloud {
-		ui.Warn("Unsupported URL Format", "gitr does not support clone using browser URLs for BitBucket. Please use SSH or HTTPS clone URLs instead.")
-		return "", nil
+	sshCloneUrl, err := GetSshCloneUrl(s, repoPath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get ssh clone url")
 	}
-	sshCloneUrl := GetSshCloneUrl(s.Hostname, repoPath)
 	ui.Cloning(sshCloneUrl)
-	if err := sshClone(sshCloneUrl, repoLocation); err != nil {
-		// Check if the error indicates the repository doesn't exist
-		// In this case, HTTP fallback won't help and would show a confusing auth error
-		if isRepoNotFoundError(err) {
-			return "", errors.New("repository not found. Please verify the URL exists and you have access")
+	if err := backend.Clone(sshCloneUrl, repoLocation, chain, opts); err != nil {
+		// HTTP fallback won't help when the repository doesn't exist or the disk is full,
+		// and would show a confusing auth error instead
+		if errors.Is(err, ErrRepoNotFound) || errors.Is(err, ErrDiskFull) {
+			return "", errors.Wrap(err, "error cloning the repo")
 		}
 		// Clean up the directory from failed SSH clone before trying HTTP
 		if err := os.RemoveAll(repoLocation); err != nil {
 			log.Debugf("failed to clean up directory after SSH clone failure: %v", err)
 		}
 		log.Debugf("SSH clone failed, trying HTTP fallback: %v", err)
-		httpCloneUrl := GetHttpCloneUrl(s.Hostname, repoPath, s.Scheme)
-		if err := httpClone(httpCloneUrl, repoLocation); err != nil {
+		httpCloneUrl, err := GetHttpCloneUrl(s, repoPath)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get http clone url")
+		}
+		if err := backend.Clone(httpCloneUrl, repoLocation, chain, opts); err != nil {
 			return "", errors.Wrap(err, "error cloning the repo using http")
 		}
 	}
 	return repoLocation, nil
 }
 
-// isRepoNotFoundError checks if the error indicates the repository doesn't exist
-func isRepoNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errStr := strings.ToLower(err.Error())
-	// These patterns are specific to repository-not-found errors from git hosts.
-	// Avoid overly broad patterns like "not found" which match unrelated errors
-	// (e.g., "host not found", "key not found", network errors).
-	notFoundPatterns := []string{
-		"repository not found",
-		"repo not found",
-		"remote: repository not found",
-		"project not found",
-		"the project you were looking for could not be found", // GitLab
-		"error: repository '", // Start of git's "repository 'X' not found" message
-	}
-	for _, pattern := range notFoundPatterns {
-		if strings.Contains(errStr, pattern) {
-			return true
-		}
-	}
-	return false
-}
-
 func GetClonePath(cfg *config.GitrConfig, inputUrl string, creDir bool) (string, error) {
 	// Strip query parameters and fragments from URLs (handles browser URLs with tracking params like ?utm_source=...)
 	inputUrl = url.StripQueryParams(inputUrl)
+	inputUrl, err := url.ExpandShorthand(cfg, inputUrl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve repo reference")
+	}
 
 	s, err := config.GetScmHost(cfg, url.GetHostname(inputUrl))
 	if err != nil {
@@ -144,12 +241,14 @@ func GetClonePath(cfg *config.GitrConfig, inputUrl string, creDir bool) (string,
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get scm home dir")
 	}
+	// owners of sourcehut and bitbucket data center personal repos start with ~, which is left out of the clone dirs
+	repoDir := strings.TrimPrefix(repoPath, "~")
 	clonePath := ""
 	if creDir || s.Clone.AlwaysCreDir {
 		if s.Clone.IncludeHostForCreDir {
-			clonePath = fmt.Sprintf("%s/%s", s.Hostname, repoPath)
+			clonePath = fmt.Sprintf("%s/%s", s.Hostname, repoDir)
 		} else {
-			clonePath = repoPath
+			clonePath = repoDir
 		}
 	} else {
 		clonePath = repoName
@@ -160,132 +259,7 @@ func GetClonePath(cfg *config.GitrConfig, inputUrl string, creDir bool) (string,
 	return clonePath, nil
 }
 
-func setUpSshAuth(hostname string) (*ssh2.PublicKeys, error) {
-	sshKeyPath, err := intssh.GetKeyPath(hostname)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get ssh config path")
-	}
-	if !file.IsFileExists(sshKeyPath) {
-		log.Debugf("%s file not found", sshKeyPath)
-		return nil, errors.Errorf("ssh auth not found")
-	}
-	pem, err := ioutil.ReadFile(sshKeyPath)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read %s file", sshKeyPath)
-	}
-	signer, err := ssh.ParsePrivateKey(pem)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to parse private key %s", sshKeyPath)
-	}
-	return &ssh2.PublicKeys{User: "git", Signer: signer}, nil
-}
-
-func getHttpsCloneToken(hostname string) (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", errors.Wrap(err, "failed to get user home dir")
-	}
-	pAccessTokenFilePath := filepath.Join(homeDir, ".personal_access_tokens", hostname)
-	pAccessTokenFileAbsPath, err := file.GetAbsPath(pAccessTokenFilePath)
-	if !file.IsFileExists(pAccessTokenFileAbsPath) {
-		return "", nil
-	}
-	pem, err := ioutil.ReadFile(pAccessTokenFileAbsPath)
-	if err != nil {
-		return "", errors.Wrapf(err, "failed to read %s file", pAccessTokenFileAbsPath)
-	}
-	return string(pem), nil
-}
-
-func httpClone(url, clonePath string) error {
-	if err := os.MkdirAll(clonePath, os.ModePerm); err != nil {
-		return errors.Wrapf(err, "failed to created dir %s", clonePath)
-	}
-
-	// Create fancy progress display
-	display := ui.NewCloneProgressDisplay()
-	display.Start()
-
-	// go-git will output progress
-	_, err := git.PlainClone(clonePath, false, &git.CloneOptions{
-		URL:      url,
-		Progress: display.Writer(),
-	})
-
-	// Stop the progress display
-	display.Stop()
-
-	return err
-}
-
-func httpsGitClone(repoUrl, token, clonePath string) error {
-	if err := os.MkdirAll(clonePath, os.ModePerm); err != nil {
-		return errors.Wrapf(err, "failed to created dir %s", clonePath)
-	}
-
-	// Create fancy progress display
-	display := ui.NewCloneProgressDisplay()
-	display.Start()
-
-	// go-git will output progress
-	_, err := git.PlainClone(clonePath, false, &git.CloneOptions{
-		URL:      repoUrl,
-		Progress: display.Writer(),
-		Auth: &http.BasicAuth{
-			Username: "abc123", // this can be anything except an empty string
-			Password: token,
-		},
-	})
-
-	// Stop the progress display
-	display.Stop()
-
-	if err != nil {
-		return errors.Wrapf(err, "failed to clone repo using personal access token %s", token)
-	}
-	return err
-}
-
-func sshClone(repoUrl, clonePath string) error {
-	if err := os.MkdirAll(clonePath, os.ModePerm); err != nil {
-		return errors.Wrapf(err, "failed to create dir %s", clonePath)
-	}
-
-	// Create fancy progress display
-	display := ui.NewCloneProgressDisplay()
-	display.Start()
-
-	// Use --progress to force git to show progress even when stderr is not a TTY
-	cmd := exec.Command("git", "clone", "--progress", repoUrl, clonePath)
-
-	// Capture stderr for both progress parsing and error detection
-	var stderrBuf strings.Builder
-	cmd.Stderr = io.MultiWriter(display.Writer(), &stderrBuf)
-	cmd.Stdout = io.Discard // Suppress stdout since we're showing fancy progress
-
-	err := cmd.Run()
-
-	// Stop the progress display
-	display.Stop()
-
-	if err != nil {
-		stderrStr := stderrBuf.String()
-		// Include stderr in the error so we can detect specific failure reasons
-		return errors.Errorf("clone failed: %s", stderrStr)
-	}
-
-	return nil
-}
-
-func GetSshCloneUrl(hostname, repoPath string) string {
-	return fmt.Sprintf("git@%s:%s.git", hostname, repoPath)
-}
-
-func GetHttpCloneUrl(hostname, repoPath string, scheme config.HttpScheme) string {
-	return fmt.Sprintf("%s://%s/%s.git", scheme, hostname, repoPath)
-}
-
-func printGitrCloneInfo(cfg *config.GitrConfig, inputUrl string, creDir bool) error {
+func printGitrCloneInfo(cfg *config.GitrConfig, inputUrl string, opts *Options) error {
 	s, err := config.GetScmHost(cfg, url.GetHostname(inputUrl))
 	repoPath, err := url.GetRepoPath(inputUrl, s.Hostname, s.Provider)
 	if err != nil {
@@ -296,10 +270,18 @@ func printGitrCloneInfo(cfg *config.GitrConfig, inputUrl string, creDir bool) er
 	if err != nil {
 		return errors.Wrap(err, "failed to get scm home dir")
 	}
-	clonePath, err := GetClonePath(cfg, inputUrl, creDir)
+	clonePath, err := GetClonePath(cfg, inputUrl, opts.CreDir)
 	if err != nil {
 		return errors.Wrap(err, "failed to get clone path")
 	}
+	sshCloneUrl, err := GetSshCloneUrl(s, repoPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to get ssh clone url")
+	}
+	httpCloneUrl, err := GetHttpCloneUrl(s, repoPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to get http clone url")
+	}
 	println("")
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
@@ -311,12 +293,36 @@ func printGitrCloneInfo(cfg *config.GitrConfig, inputUrl string, creDir bool) er
 	t.AppendSeparator()
 	t.AppendRow(table.Row{"repo-name", repoName})
 	t.AppendSeparator()
-	t.AppendRow(table.Row{"ssh-url", GetSshCloneUrl(s.Hostname, repoPath)})
+	t.AppendRow(table.Row{"ssh-url", sshCloneUrl})
 	t.AppendSeparator()
-	t.AppendRow(table.Row{"http-url", GetHttpCloneUrl(s.Hostname, repoPath, s.Scheme)})
+	t.AppendRow(table.Row{"http-url", httpCloneUrl})
 	t.AppendSeparator()
-	t.AppendRow(table.Row{"create-dir", s.Clone.AlwaysCreDir || creDir})
+	t.AppendRow(table.Row{"create-dir", s.Clone.AlwaysCreDir || opts.CreDir})
 	t.AppendSeparator()
+	if opts.Depth > 0 {
+		t.AppendRow(table.Row{"depth", opts.Depth})
+		t.AppendSeparator()
+	}
+	if opts.Filter != "" {
+		t.AppendRow(table.Row{"filter", opts.Filter})
+		t.AppendSeparator()
+	}
+	if opts.SingleBranch {
+		t.AppendRow(table.Row{"single-branch", opts.SingleBranch})
+		t.AppendSeparator()
+	}
+	if len(opts.SparsePaths) > 0 {
+		t.AppendRow(table.Row{"sparse-paths", strings.Join(opts.SparsePaths, ", ")})
+		t.AppendSeparator()
+	}
+	if opts.Lfs != "" {
+		t.AppendRow(table.Row{"lfs", opts.Lfs})
+		t.AppendSeparator()
+	}
+	if opts.Submodules != "" && opts.Submodules != config.SubmodulesNone {
+		t.AppendRow(table.Row{"submodules", opts.Submodules})
+		t.AppendSeparator()
+	}
 	t.AppendRow(table.Row{"scm-home", scmHome})
 	t.AppendSeparator()
 	t.AppendRow(table.Row{"clone-path", clonePath})
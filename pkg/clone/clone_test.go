@@ -91,6 +91,26 @@ func TestGetClonePath(t *testing.T) {
 			expectPath:  "/Users/joe/scm/github.com/kubernetes-sigs/kind",
 			expectedErr: nil,
 		},
+		{
+			testName: "azure devops ssh urls should be cloned to org/project/repo",
+			input: &getClonePathInput{cfg: &config.GitrConfig{Scm: &config.Scm{HomeDir: "/Users/joe/scm", Hosts: []*config.ScmHost{{Hostname: "dev.azure.com", Provider: config.AzureDevOps, Clone: &config.CloneConfig{
+				HomeDir:              "",
+				AlwaysCreDir:         true,
+				IncludeHostForCreDir: true,
+			}}}}}, inputUrl: "git@ssh.dev.azure.com:v3/org/project/repo", creDir: false},
+			expectPath:  "/Users/joe/scm/dev.azure.com/org/project/repo",
+			expectedErr: nil,
+		},
+		{
+			testName: "sourcehut owners should be cloned without the ~ prefix",
+			input: &getClonePathInput{cfg: &config.GitrConfig{Scm: &config.Scm{HomeDir: "/Users/joe/scm", Hosts: []*config.ScmHost{{Hostname: "git.sr.ht", Provider: config.SourceHut, Clone: &config.CloneConfig{
+				HomeDir:              "",
+				AlwaysCreDir:         true,
+				IncludeHostForCreDir: true,
+			}}}}}, inputUrl: "https://git.sr.ht/~user/repo/tree/main/item/README.md", creDir: false},
+			expectPath:  "/Users/joe/scm/git.sr.ht/user/repo",
+			expectedErr: nil,
+		},
 	}
 
 	t.Run("test get clone path", func(t *testing.T) {
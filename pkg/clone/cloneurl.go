@@ -0,0 +1,69 @@
+package clone
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
+)
+
+// newCloneUrlData returns the provider of the host and the clone url data of the repo,
+// with the ssh user and port of the host falling back to the defaults of the provider
+func newCloneUrlData(s *config.ScmHost, repoPath string) (provider.Provider, *provider.CloneURLData, error) {
+	prov, err := provider.ForHost(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := &provider.CloneURLData{Hostname: s.Hostname, RepoPath: repoPath, SshUser: s.SshUser, SshPort: s.SshPort, Scheme: s.Scheme}
+	defaultUser, defaultPort := prov.SshDefaults()
+	if d.SshUser == "" {
+		d.SshUser = defaultUser
+	}
+	if d.SshPort == 0 {
+		d.SshPort = defaultPort
+	}
+	if d.Scheme == "" {
+		d.Scheme = config.Https
+	}
+	return prov, d, nil
+}
+
+// GetSshCloneUrl returns the ssh clone url of the repo on the host,
+// using the ssh clone url template of the host if set and the form of the provider otherwise
+func GetSshCloneUrl(s *config.ScmHost, repoPath string) (string, error) {
+	prov, d, err := newCloneUrlData(s, repoPath)
+	if err != nil {
+		return "", err
+	}
+	if s.SshCloneUrlTemplate != "" {
+		return renderCloneUrl(s.SshCloneUrlTemplate, d)
+	}
+	return prov.SshCloneURL(d), nil
+}
+
+// GetHttpCloneUrl returns the http clone url of the repo on the host,
+// using the http clone url template of the host if set and the form of the provider otherwise
+func GetHttpCloneUrl(s *config.ScmHost, repoPath string) (string, error) {
+	prov, d, err := newCloneUrlData(s, repoPath)
+	if err != nil {
+		return "", err
+	}
+	if s.HttpCloneUrlTemplate != "" {
+		return renderCloneUrl(s.HttpCloneUrlTemplate, d)
+	}
+	return prov.HttpCloneURL(d), nil
+}
+
+func renderCloneUrl(text string, d *provider.CloneURLData) (string, error) {
+	tmpl, err := template.New("clone-url").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse clone url template %s", text)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, d); err != nil {
+		return "", errors.Wrapf(err, "failed to render clone url template %s", text)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,211 @@
+package clone
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	"github.com/swarupdonepudi/gitr/pkg/clonerr"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	gitrgit "github.com/swarupdonepudi/gitr/pkg/git"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+// execBackend clones by running the git binary
+type execBackend struct{}
+
+func (b *execBackend) Name() config.CloneBackend {
+	return config.CloneBackendExec
+}
+
+// Clone runs git clone. Over ssh git gets its keys from ssh-agent and ~/.ssh/config by itself,
+// over http the credential of the auth chain is passed to git in the environment so it never shows up in the process list.
+func (b *execBackend) Clone(repoUrl, clonePath string, chain *auth.Chain, opts *Options) error {
+	if err := os.MkdirAll(clonePath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "failed to create dir %s", clonePath)
+	}
+	if err := b.run(repoUrl, gitEnv(repoUrl, chain), gitCloneArgs(repoUrl, clonePath, opts)...); err != nil {
+		return err
+	}
+	if len(opts.SparsePaths) > 0 {
+		sparseArgs := append([]string{"-C", clonePath, "sparse-checkout", "set"}, opts.SparsePaths...)
+		if out, err := exec.Command("git", sparseArgs...).CombinedOutput(); err != nil {
+			return &BackendError{Backend: b.Name(), Url: repoUrl, Output: string(out),
+				Kind: clonerr.FromGitExit(exitCode(err), string(out)), Err: errors.Wrap(err, "failed to set sparse paths")}
+		}
+	}
+	return nil
+}
+
+// UpdateSubmodules runs git submodule update, sending the credential of the auth chain to submodules on the same host
+func (b *execBackend) UpdateSubmodules(repoLocation string, chain *auth.Chain) error {
+	originUrl := getOriginUrl(repoLocation)
+	return b.run(originUrl, gitEnv(originUrl, chain), "-C", repoLocation, "submodule", "update", "--init", "--recursive", "--progress")
+}
+
+// updateSubmoduleWithReference checks out a single submodule, borrowing the objects of the reference repo
+// through a git alternate instead of downloading them again
+func (b *execBackend) updateSubmoduleWithReference(repoLocation, submodulePath, referencePath string, chain *auth.Chain) error {
+	originUrl := getOriginUrl(repoLocation)
+	return b.run(originUrl, gitEnv(originUrl, chain), "-C", repoLocation, "submodule", "update", "--init", "--progress",
+		"--reference", referencePath, "--", submodulePath)
+}
+
+// Fetch runs git fetch, so that partial clones keep their filter
+func (b *execBackend) Fetch(repoLocation, remoteName string, refSpecs []string, depth int, chain *auth.Chain) error {
+	originUrl := getOriginUrl(repoLocation)
+	args := []string{"-C", repoLocation, "fetch", "--progress"}
+	if depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", depth))
+	}
+	args = append(append(args, remoteName), refSpecs...)
+	return b.run(originUrl, gitEnv(originUrl, chain), args...)
+}
+
+// Checkout runs git checkout, so that sparse clones keep their sparse paths
+// and the missing objects of partial clones are downloaded from the remote
+func (b *execBackend) Checkout(repoLocation string, candidates []string, chain *auth.Chain) (string, error) {
+	r, err := gogit.PlainOpen(repoLocation)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	resolved, err := gitrgit.ResolveRef(r, candidates)
+	if err != nil {
+		return "", err
+	}
+	args := []string{"-C", repoLocation, "checkout", "--progress"}
+	switch {
+	case resolved.Ref.IsBranch():
+		args = append(args, resolved.Name)
+	case resolved.Ref.IsRemote():
+		args = append(args, "--no-track", "-b", resolved.Name, resolved.Ref.String())
+	default:
+		args = append(args, "--detach", resolved.Hash.String())
+	}
+	originUrl := getOriginUrl(repoLocation)
+	if err := b.run(originUrl, gitEnv(originUrl, chain), append(args, "--")...); err != nil {
+		return "", errors.Wrapf(err, "failed to checkout %s", resolved.Name)
+	}
+	if resolved.Ref.IsRemote() {
+		return resolved.Name, gitrgit.TrackRemoteBranch(r, resolved.Name)
+	}
+	return resolved.Name, nil
+}
+
+// FastForward merges the commit into the checked out branch with git, refusing anything but a fast-forward
+func (b *execBackend) FastForward(repoLocation string, commit plumbing.Hash, chain *auth.Chain) error {
+	originUrl := getOriginUrl(repoLocation)
+	if err := b.run(originUrl, gitEnv(originUrl, chain), "-C", repoLocation, "merge", "--ff-only", commit.String()); err != nil {
+		return errors.Wrapf(err, "failed to fast-forward to %s", commit)
+	}
+	return nil
+}
+
+// gitEnv returns the env of git commands talking to the remote at repoUrl.
+// LFS objects are not downloaded during checkout, they are pulled afterwards with their own progress.
+func gitEnv(repoUrl string, chain *auth.Chain) []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_LFS_SKIP_SMUDGE=1")
+	if auth.TransportOf(repoUrl) == auth.TransportHttp {
+		return append(env, gitAuthEnv(repoUrl, chain.Authenticate(auth.TransportHttp))...)
+	}
+	log.Debugf("ssh credentials for %s are resolved by git", repoUrl)
+	return env
+}
+
+// run runs git with its progress shown in the clone progress display
+func (b *execBackend) run(repoUrl string, env []string, args ...string) error {
+	// Create fancy progress display
+	display := ui.NewCloneProgressDisplay()
+	display.Start()
+
+	// Use --progress to force git to show progress even when stderr is not a TTY
+	cmd := exec.Command("git", args...)
+	cmd.Env = env
+
+	// Capture stderr for both progress parsing and error detection
+	var stderrBuf strings.Builder
+	cmd.Stderr = io.MultiWriter(display.Writer(), &stderrBuf)
+	cmd.Stdout = io.Discard // Suppress stdout since we're showing fancy progress
+
+	err := cmd.Run()
+
+	// Stop the progress display
+	display.Stop()
+
+	if err != nil {
+		// Include stderr in the error since git reports the cause of every fatal error there
+		return &BackendError{Backend: b.Name(), Url: repoUrl, Output: stderrBuf.String(),
+			Kind: clonerr.FromGitExit(exitCode(err), stderrBuf.String()), Err: err}
+	}
+	return nil
+}
+
+// exitCode returns the exit code of a failed command, or -1 when the command did not run to completion
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// gitCloneArgs returns the arguments of the git clone command for the clone options
+func gitCloneArgs(repoUrl, clonePath string, opts *Options) []string {
+	args := []string{"clone", "--progress"}
+	if opts.Depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", opts.Depth))
+	}
+	if opts.Filter != "" {
+		args = append(args, fmt.Sprintf("--filter=%s", opts.Filter))
+	}
+	if opts.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+	if len(opts.SparsePaths) > 0 {
+		args = append(args, "--sparse")
+	}
+	return append(args, repoUrl, clonePath)
+}
+
+// gitAuthEnv returns the env vars that make git send the credential as an authorization header
+// to the host of the repo url only, using the GIT_CONFIG_* variables supported since git 2.31.
+// The header is added after the GIT_CONFIG_* entries already exported by the user.
+func gitAuthEnv(repoUrl string, cred *auth.Credential) []string {
+	if cred == nil {
+		return nil
+	}
+	basic, ok := cred.Method.(*http.BasicAuth)
+	if !ok {
+		return nil
+	}
+	count, err := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	if err != nil || count < 0 {
+		count = 0
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(basic.Username + ":" + basic.Password))
+	return []string{
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", count+1),
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=http.%s.extraHeader", count, hostUrl(repoUrl)),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=Authorization: Basic %s", count, encoded),
+	}
+}
+
+// hostUrl returns the scheme and host of an http url, ex: https://github.com/
+func hostUrl(repoUrl string) string {
+	scheme, rest, found := strings.Cut(repoUrl, "://")
+	if !found {
+		return repoUrl
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return fmt.Sprintf("%s://%s/", scheme, host)
+}
@@ -0,0 +1,124 @@
+package clone
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/leftbin/go-util/pkg/file"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+// ErrExistingDir is returned when the clone path is a directory that is not a git repo
+// and the existing dir policy does not allow replacing it
+var ErrExistingDir = errors.New("clone path exists and is not a git repository")
+
+// backupTimeFormat is the format of the timestamp added to directories that are moved aside
+const backupTimeFormat = "20060102-150405"
+
+// prepareCloneDir applies the existing dir policy when the clone path is a directory that is not a git repo.
+// Empty directories are left in place since cloning into them is safe.
+// Directories replaced by the clone, with the prompt and overwrite policies, are moved aside and their new path is returned,
+// to be removed with removeReplacedDir once the clone succeeded or moved back with restoreReplacedDir when it failed.
+func prepareCloneDir(repoLocation string, policy config.ExistingDirPolicy) (string, error) {
+	if !file.IsDirExists(repoLocation) {
+		return "", nil
+	}
+	entries, err := os.ReadDir(repoLocation)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read %s dir", repoLocation)
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
+	switch policy {
+	case "", config.ExistingDirFail:
+		return "", errors.Wrap(ErrExistingDir, repoLocation)
+	case config.ExistingDirBackup:
+		backupPath := fmt.Sprintf("%s.bak-%s", repoLocation, time.Now().Format(backupTimeFormat))
+		if err := os.Rename(repoLocation, backupPath); err != nil {
+			return "", errors.Wrapf(err, "failed to move %s dir to %s", repoLocation, backupPath)
+		}
+		ui.ExistingDirBackedUp(repoLocation, backupPath)
+		return "", nil
+	case config.ExistingDirPrompt:
+		if !ui.Confirm(fmt.Sprintf("%s is not a git repository. Delete it and clone?", repoLocation)) {
+			return "", errors.Wrap(ErrExistingDir, repoLocation)
+		}
+		return moveAside(repoLocation)
+	case config.ExistingDirOverwrite:
+		return moveAside(repoLocation)
+	default:
+		return "", errors.Errorf("unknown onExistingDir policy %s, expecting one of %s, %s, %s or %s", policy,
+			config.ExistingDirFail, config.ExistingDirBackup, config.ExistingDirPrompt, config.ExistingDirOverwrite)
+	}
+}
+
+func moveAside(repoLocation string) (string, error) {
+	replacedPath := fmt.Sprintf("%s.gitr-replaced-%s", repoLocation, time.Now().Format(backupTimeFormat))
+	if err := os.Rename(repoLocation, replacedPath); err != nil {
+		return "", errors.Wrapf(err, "failed to move %s dir to %s", repoLocation, replacedPath)
+	}
+	return replacedPath, nil
+}
+
+// removeReplacedDir deletes the directory replaced by a successful clone
+func removeReplacedDir(replacedPath string, opts *Options) {
+	if replacedPath == "" {
+		return
+	}
+	if err := os.RemoveAll(replacedPath); err != nil {
+		opts.warn("Replaced Directory Not Removed",
+			fmt.Sprintf("The clone succeeded but the directory it replaced could not be removed from %s: %v", replacedPath, err))
+	}
+}
+
+// restoreReplacedDir moves the directory replaced by a failed clone back to the clone path,
+// which must have been cleaned up first
+func restoreReplacedDir(replacedPath, repoLocation string, opts *Options) {
+	if replacedPath == "" {
+		return
+	}
+	if err := os.Rename(replacedPath, repoLocation); err != nil {
+		opts.warn("Existing Directory Not Restored",
+			fmt.Sprintf("The clone failed and %s could not be moved back from %s: %v", repoLocation, replacedPath, err))
+	}
+}
+
+// firstMissingDir returns the top most directory of dir that does not exist yet,
+// which is the directory to remove to undo everything a failed clone created.
+// An empty string is returned when dir already exists.
+func firstMissingDir(dir string) string {
+	missing := ""
+	for d := filepath.Clean(dir); !file.IsDirExists(d); d = filepath.Dir(d) {
+		missing = d
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+	return missing
+}
+
+// cleanUpFailedClone removes everything a failed clone created.
+// When the clone path existed before the clone it was empty, so only its contents are removed.
+func cleanUpFailedClone(repoLocation, createdDir string) {
+	if createdDir != "" {
+		if err := os.RemoveAll(createdDir); err != nil {
+			log.Debugf("failed to clean up %s after failed clone: %v", createdDir, err)
+		}
+		return
+	}
+	entries, err := os.ReadDir(repoLocation)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(repoLocation, e.Name())); err != nil {
+			log.Debugf("failed to clean up %s after failed clone: %v", e.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package clone
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leftbin/go-util/pkg/file"
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+func TestPrepareCloneDir(t *testing.T) {
+	setUp := func(t *testing.T) string {
+		dir := filepath.Join(t.TempDir(), "repo")
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep me"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		return dir
+	}
+	t.Run("existing dir should be kept by default", func(t *testing.T) {
+		dir := setUp(t)
+		_, err := prepareCloneDir(dir, "")
+		if !errors.Is(err, ErrExistingDir) {
+			t.Errorf("expecting existing dir error but got %v", err)
+		}
+		if !file.IsFileExists(filepath.Join(dir, "notes.txt")) {
+			t.Errorf("expecting files of %s to be kept", dir)
+		}
+	})
+	t.Run("existing dir should be moved aside with backup policy", func(t *testing.T) {
+		dir := setUp(t)
+		if _, err := prepareCloneDir(dir, config.ExistingDirBackup); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if file.IsDirExists(dir) {
+			t.Errorf("expecting %s to be moved", dir)
+		}
+		backups, _ := filepath.Glob(dir + ".bak-*")
+		if len(backups) != 1 || !file.IsFileExists(filepath.Join(backups[0], "notes.txt")) {
+			t.Errorf("expecting one backup with the files of %s but got %v", dir, backups)
+		}
+	})
+	t.Run("existing dir should be moved aside with overwrite policy and removed after the clone", func(t *testing.T) {
+		dir := setUp(t)
+		replacedPath, err := prepareCloneDir(dir, config.ExistingDirOverwrite)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if file.IsDirExists(dir) || !file.IsFileExists(filepath.Join(replacedPath, "notes.txt")) {
+			t.Errorf("expecting %s to be moved to %s", dir, replacedPath)
+		}
+		removeReplacedDir(replacedPath, &Options{})
+		if file.IsDirExists(replacedPath) {
+			t.Errorf("expecting %s to be removed", replacedPath)
+		}
+	})
+	t.Run("existing dir replaced by a failed clone should be restored", func(t *testing.T) {
+		dir := setUp(t)
+		replacedPath, err := prepareCloneDir(dir, config.ExistingDirOverwrite)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		createdDir := firstMissingDir(dir)
+		if err := os.MkdirAll(filepath.Join(dir, ".git"), os.ModePerm); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		cleanUpFailedClone(dir, createdDir)
+		restoreReplacedDir(replacedPath, dir, &Options{})
+		if !file.IsFileExists(filepath.Join(dir, "notes.txt")) || file.IsDirExists(filepath.Join(dir, ".git")) {
+			t.Errorf("expecting the files of %s to be restored", dir)
+		}
+		if file.IsDirExists(replacedPath) {
+			t.Errorf("expecting %s to be moved back", replacedPath)
+		}
+	})
+	t.Run("empty dir should be used as is", func(t *testing.T) {
+		dir := t.TempDir()
+		if _, err := prepareCloneDir(dir, config.ExistingDirFail); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("unknown policy should be an error", func(t *testing.T) {
+		dir := setUp(t)
+		_, err := prepareCloneDir(dir, "keep")
+		if err == nil || !strings.Contains(err.Error(), "unknown onExistingDir policy") {
+			t.Errorf("expecting unknown policy error but got %v", err)
+		}
+	})
+}
+
+func TestCleanUpFailedClone(t *testing.T) {
+	t.Run("directories created for the clone should be removed", func(t *testing.T) {
+		scmHome := t.TempDir()
+		repoLocation := filepath.Join(scmHome, "github.com", "owner", "repo")
+		createdDir := firstMissingDir(repoLocation)
+		if createdDir != filepath.Join(scmHome, "github.com") {
+			t.Fatalf("expecting %s to be the first missing dir but got %s", filepath.Join(scmHome, "github.com"), createdDir)
+		}
+		if err := os.MkdirAll(filepath.Join(repoLocation, ".git"), os.ModePerm); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		cleanUpFailedClone(repoLocation, createdDir)
+		if file.IsDirExists(createdDir) || !file.IsDirExists(scmHome) {
+			t.Errorf("expecting only %s to be removed", createdDir)
+		}
+	})
+	t.Run("existing empty clone path should be emptied but kept", func(t *testing.T) {
+		repoLocation := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(repoLocation, ".git"), os.ModePerm); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		cleanUpFailedClone(repoLocation, "")
+		entries, err := os.ReadDir(repoLocation)
+		if err != nil || len(entries) != 0 {
+			t.Errorf("expecting %s to be empty but got %v, %v", repoLocation, entries, err)
+		}
+	})
+}
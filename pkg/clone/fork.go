@@ -0,0 +1,100 @@
+package clone
+
+import (
+	"fmt"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	gitrgit "github.com/swarupdonepudi/gitr/pkg/git"
+	"github.com/swarupdonepudi/gitr/pkg/scmapi"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+	"github.com/swarupdonepudi/gitr/pkg/url"
+)
+
+// ErrForkRef is returned when the url of a repo to fork points at a branch, tag or pull request,
+// which the fork does not necessarily have
+var ErrForkRef = errors.New("forking is only supported for urls of the repo itself")
+
+// CloneFork finds or creates the fork of the repo owned by the authenticated user, clones the fork
+// to its deterministic path and adds the original repo as the upstream remote of the clone
+func CloneFork(cfg *config.GitrConfig, inputUrl string, opts *Options) (string, error) {
+	inputUrl = url.StripQueryParams(inputUrl)
+	inputUrl, err := url.ExpandShorthand(cfg, inputUrl)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve repo reference")
+	}
+	s, err := config.GetScmHost(cfg, url.GetHostname(inputUrl))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to fork git repo with %s url", inputUrl)
+	}
+	upstreamPath, err := url.GetRepoPath(inputUrl, s.Hostname, s.Provider)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get repo path")
+	}
+	ref, err := url.GetRef(inputUrl, s.Hostname, s.Provider)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get ref from url")
+	}
+	if ref != nil {
+		return "", errors.Wrapf(ErrForkRef, "%s points at a branch, tag or pull request, use the url of %s", inputUrl, upstreamPath)
+	}
+	if opts.Dry {
+		return Clone(cfg, inputUrl, opts)
+	}
+	chain, err := auth.NewChain(s, opts.Token)
+	if err != nil {
+		return "", err
+	}
+	token := chain.Authenticate(auth.TransportHttp).Token()
+
+	fork, err := scmapi.GetOrCreateFork(s, upstreamPath, token)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get fork of %s", upstreamPath)
+	}
+	ui.ForkReady(fork.FullPath, upstreamPath, fork.Created)
+
+	scheme := s.Scheme
+	if scheme == "" {
+		scheme = config.Https
+	}
+	forkUrl := fmt.Sprintf("%s://%s/%s", scheme, s.Hostname, fork.FullPath)
+	repoLocation, err := Clone(cfg, forkUrl, opts)
+	if err != nil {
+		return repoLocation, err
+	}
+	if err := addUpstreamRemote(repoLocation, fork.FullPath, upstreamPath); err != nil {
+		return repoLocation, err
+	}
+	return repoLocation, nil
+}
+
+// addUpstreamRemote adds the upstream remote to the clone of a fork,
+// using the same transport as the origin remote of the clone
+func addUpstreamRemote(repoLocation, forkPath, upstreamPath string) error {
+	r, err := gogit.PlainOpen(repoLocation)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	originUrl, err := gitrgit.GetGitRemoteUrl(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to get origin url")
+	}
+	upstreamUrl := GetUpstreamUrl(originUrl, forkPath, upstreamPath)
+	if err := gitrgit.AddRemote(r, gitrgit.UpstreamRemoteName, upstreamUrl); err != nil {
+		return err
+	}
+	ui.Info(fmt.Sprintf("Added %s remote %s", gitrgit.UpstreamRemoteName, ui.Path(upstreamUrl)))
+	return nil
+}
+
+// GetUpstreamUrl returns the url of the upstream repo by replacing the fork path in the origin url
+func GetUpstreamUrl(originUrl, forkPath, upstreamPath string) string {
+	idx := strings.LastIndex(originUrl, forkPath)
+	if idx == -1 {
+		return originUrl
+	}
+	return originUrl[:idx] + upstreamPath + originUrl[idx+len(forkPath):]
+}
@@ -0,0 +1,158 @@
+package clone
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	"github.com/swarupdonepudi/gitr/pkg/clonerr"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	gitrgit "github.com/swarupdonepudi/gitr/pkg/git"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+	"github.com/swarupdonepudi/gitr/pkg/url"
+)
+
+// goGitBackend clones with the embedded go-git library and needs no git binary
+type goGitBackend struct{}
+
+func (b *goGitBackend) Name() config.CloneBackend {
+	return config.CloneBackendGoGit
+}
+
+// Clone clones the repo with go-git using the credential of the auth chain for the transport of the url
+func (b *goGitBackend) Clone(repoUrl, clonePath string, chain *auth.Chain, opts *Options) error {
+	if err := os.MkdirAll(clonePath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "failed to created dir %s", clonePath)
+	}
+	cred := chain.Authenticate(auth.TransportOf(repoUrl))
+
+	// Create fancy progress display
+	display := ui.NewCloneProgressDisplay()
+	display.Start()
+
+	// go-git will output progress
+	cloneOptions := goGitCloneOptions(repoUrl, opts)
+	cloneOptions.Progress = display.Writer()
+	cloneOptions.Auth = cred.AuthMethod()
+	r, err := git.PlainClone(clonePath, false, cloneOptions)
+
+	// Stop the progress display
+	display.Stop()
+
+	if err != nil {
+		kind := clonerr.FromGoGit(err)
+		if cred != nil {
+			err = errors.Wrapf(err, "failed to clone repo using %s credentials", cred.Source)
+		}
+		return &BackendError{Backend: b.Name(), Url: repoUrl, Kind: kind, Err: err}
+	}
+	if err := goGitSparseCheckout(r, opts); err != nil {
+		return &BackendError{Backend: b.Name(), Url: repoUrl, Kind: clonerr.FromGoGit(err), Err: err}
+	}
+	return nil
+}
+
+// goGitCloneOptions translates the clone options into go-git clone options.
+// go-git does not support partial clones, so a filter results in a full clone.
+func goGitCloneOptions(repoUrl string, opts *Options) *git.CloneOptions {
+	if opts.Filter != "" {
+		opts.warn("Partial Clone Not Supported",
+			fmt.Sprintf("The %s filter is ignored by the gogit clone backend, all objects will be downloaded.", opts.Filter))
+	}
+	return &git.CloneOptions{
+		URL:          repoUrl,
+		Depth:        opts.Depth,
+		SingleBranch: opts.SingleBranch,
+		NoCheckout:   len(opts.SparsePaths) > 0,
+	}
+}
+
+// goGitSparseCheckout checks out only the sparse paths when the clone was created without a checkout
+func goGitSparseCheckout(r *git.Repository, opts *Options) error {
+	if len(opts.SparsePaths) == 0 {
+		return nil
+	}
+	head, err := r.Head()
+	if err != nil {
+		return errors.Wrap(err, "failed to get head of cloned repo")
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "failed to get worktree of cloned repo")
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{
+		Branch:                    head.Name(),
+		SparseCheckoutDirectories: opts.SparsePaths,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to checkout sparse paths %s", strings.Join(opts.SparsePaths, ", "))
+	}
+	return nil
+}
+
+// UpdateSubmodules initializes and checks out the submodules with go-git.
+// Submodules on the host of the auth chain use its credentials, others are fetched without.
+func (b *goGitBackend) UpdateSubmodules(repoLocation string, chain *auth.Chain) error {
+	r, err := git.PlainOpen(repoLocation)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "failed to get worktree of cloned repo")
+	}
+	subs, err := wt.Submodules()
+	if err != nil {
+		return errors.Wrap(err, "failed to read submodules")
+	}
+	for _, sub := range subs {
+		subUrl := sub.Config().URL
+		updateOptions := &git.SubmoduleUpdateOptions{Init: true, RecurseSubmodules: git.DefaultSubmoduleRecursionDepth}
+		if t := auth.TransportOf(subUrl); t != "" && url.GetHostname(subUrl) == chain.Hostname() {
+			updateOptions.Auth = chain.Authenticate(t).AuthMethod()
+		}
+		if err := sub.Update(updateOptions); err != nil {
+			return &BackendError{Backend: b.Name(), Url: subUrl, Kind: clonerr.FromGoGit(err),
+				Err: errors.Wrapf(err, "failed to update submodule %s", sub.Config().Path)}
+		}
+	}
+	return nil
+}
+
+// Fetch fetches the refspecs with go-git using the credential of the auth chain for the transport of the remote
+func (b *goGitBackend) Fetch(repoLocation, remoteName string, refSpecs []string, depth int, chain *auth.Chain) error {
+	r, err := git.PlainOpen(repoLocation)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	return gitrgit.Fetch(r, remoteName, refSpecs, depth, remoteAuth(r, chain))
+}
+
+// Checkout checks out the first candidate naming a branch or a tag with go-git
+func (b *goGitBackend) Checkout(repoLocation string, candidates []string, chain *auth.Chain) (string, error) {
+	r, err := git.PlainOpen(repoLocation)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	return gitrgit.CheckoutRef(r, candidates)
+}
+
+// FastForward resets the checked out branch to the commit with go-git.
+// A merge reset updates the files that differ between the branch and the commit, which is a fast-forward for clean worktrees.
+func (b *goGitBackend) FastForward(repoLocation string, commit plumbing.Hash, chain *auth.Chain) error {
+	r, err := git.PlainOpen(repoLocation)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "failed to get worktree")
+	}
+	if err := wt.Reset(&git.ResetOptions{Commit: commit, Mode: git.MergeReset}); err != nil {
+		return errors.Wrapf(err, "failed to fast-forward to %s", commit)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package clone
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+// pullLfsObjects downloads the git lfs objects of a fresh clone that tracks files with lfs.
+// Clones keep the lfs pointer files, with a warning, when lfs is skipped in the config or git-lfs is not installed.
+func pullLfsObjects(repoLocation string, chain *auth.Chain, opts *Options) error {
+	usesLfs, err := UsesLfs(repoLocation)
+	if err != nil {
+		return err
+	}
+	if !usesLfs {
+		return nil
+	}
+	switch opts.Lfs {
+	case "", config.LfsPull:
+	case config.LfsSkip:
+		warnLfsSkipped(repoLocation, "lfs is set to skip in the clone config", opts)
+		return nil
+	default:
+		return errors.Errorf("unknown lfs mode %s, expecting %s or %s", opts.Lfs, config.LfsPull, config.LfsSkip)
+	}
+	if _, err := exec.LookPath("git-lfs"); err != nil {
+		warnLfsSkipped(repoLocation, "git-lfs is not installed", opts)
+		return nil
+	}
+	originUrl := getOriginUrl(repoLocation)
+	env := append(gitEnv(originUrl, chain), "GIT_LFS_FORCE_PROGRESS=1")
+	if err := (&execBackend{}).run(originUrl, env, "-C", repoLocation, "lfs", "pull"); err != nil {
+		return errors.Wrap(err, "failed to pull lfs objects")
+	}
+	return nil
+}
+
+func warnLfsSkipped(repoLocation, reason string, opts *Options) {
+	opts.warn("LFS Objects Not Downloaded",
+		fmt.Sprintf("%s tracks files with Git LFS but only pointer files were checked out: %s.\n"+
+			"Install git-lfs and run git lfs pull in the repo to download them.", repoLocation, reason))
+}
+
+// UsesLfs reports whether any .gitattributes file tracked by the repo tracks files with git lfs.
+// The attributes files are looked up in the index rather than by walking the worktree, which can be large.
+// Attributes files whose blob is missing from a partial clone are skipped.
+func UsesLfs(repoLocation string) (bool, error) {
+	r, err := gogit.PlainOpen(repoLocation)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read index of %s", repoLocation)
+	}
+	for _, e := range idx.Entries {
+		if path.Base(e.Name) != ".gitattributes" {
+			continue
+		}
+		blob, err := r.BlobObject(e.Hash)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			log.Debugf("skipping %s since its blob is not in the clone", e.Name)
+			continue
+		}
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to read %s", e.Name)
+		}
+		found, err := hasLfsFilter(blob)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to read %s", e.Name)
+		}
+		if found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func hasLfsFilter(blob *object.Blob) (bool, error) {
+	rd, err := blob.Reader()
+	if err != nil {
+		return false, err
+	}
+	defer rd.Close()
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, attr := range strings.Fields(line) {
+			if attr == "filter=lfs" {
+				return true, nil
+			}
+		}
+	}
+	return false, scanner.Err()
+}
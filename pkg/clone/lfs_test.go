@@ -0,0 +1,66 @@
+package clone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+func TestUsesLfs(t *testing.T) {
+	var tests = []struct {
+		name       string
+		attributes map[string]string
+		expected   bool
+	}{
+		{"root attributes with lfs filter", map[string]string{".gitattributes": "*.psd filter=lfs diff=lfs merge=lfs -text\n"}, true},
+		{"nested attributes with lfs filter", map[string]string{"assets/.gitattributes": "*.png filter=lfs diff=lfs merge=lfs -text\n"}, true},
+		{"attributes without lfs filter", map[string]string{".gitattributes": "*.sh text eol=lf\n# *.bin filter=lfs\n"}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			files := map[string]string{"README.md": "readme"}
+			for name, content := range tc.attributes {
+				files[name] = content
+			}
+			dir := newTestRepo(t, files, 1)
+			got, err := UsesLfs(dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expecting %v but got %v", tc.expected, got)
+			}
+		})
+	}
+	t.Run("untracked attributes", func(t *testing.T) {
+		dir := newTestRepo(t, map[string]string{"README.md": "readme"}, 1)
+		if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("*.bin filter=lfs\n"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		if got, err := UsesLfs(dir); err != nil || got {
+			t.Errorf("expecting untracked attributes to be ignored but got %v, %v", got, err)
+		}
+	})
+}
+
+func TestPullLfsObjects(t *testing.T) {
+	dir := newTestRepo(t, map[string]string{".gitattributes": "*.psd filter=lfs\n"}, 1)
+	t.Run("skip mode should keep pointer files without an error", func(t *testing.T) {
+		if err := pullLfsObjects(dir, nil, &Options{Lfs: config.LfsSkip}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("missing git-lfs should not fail the clone", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		if err := pullLfsObjects(dir, nil, &Options{Lfs: config.LfsPull}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("unknown mode should be an error", func(t *testing.T) {
+		if err := pullLfsObjects(dir, nil, &Options{Lfs: "fetch"}); err == nil {
+			t.Errorf("expecting an error")
+		}
+	})
+}
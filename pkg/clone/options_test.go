@@ -0,0 +1,158 @@
+package clone
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+func TestOptionsWithHostDefaults(t *testing.T) {
+	hostCfg := &config.CloneConfig{Depth: 1, Filter: "blob:none", SingleBranch: true, SparsePaths: []string{"docs"}}
+	t.Run("unset options should fall back to the host clone config", func(t *testing.T) {
+		opts := (&Options{}).withHostDefaults(hostCfg)
+		if opts.Depth != 1 || opts.Filter != "blob:none" || !opts.SingleBranch || len(opts.SparsePaths) != 1 {
+			t.Errorf("expecting host defaults but got %+v", opts)
+		}
+	})
+	t.Run("options set for the invocation should take precedence", func(t *testing.T) {
+		opts := (&Options{Depth: 10, Filter: "tree:0", SparsePaths: []string{"cmd", "pkg"}}).withHostDefaults(hostCfg)
+		if opts.Depth != 10 || opts.Filter != "tree:0" || len(opts.SparsePaths) != 2 {
+			t.Errorf("expecting invocation options but got %+v", opts)
+		}
+	})
+	t.Run("explicit zero values should override the host clone config", func(t *testing.T) {
+		opts := (&Options{Explicit: map[Option]bool{OptionDepth: true, OptionSingleBranch: true}}).withHostDefaults(hostCfg)
+		if opts.Depth != 0 || opts.SingleBranch {
+			t.Errorf("expecting a full clone of all branches but got %+v", opts)
+		}
+		if opts.Filter != "blob:none" {
+			t.Errorf("expecting the host filter for options without explicit values but got %s", opts.Filter)
+		}
+	})
+}
+
+func TestGitCloneArgs(t *testing.T) {
+	var tests = []struct {
+		opts     *Options
+		expected string
+	}{
+		{&Options{}, "clone --progress git@github.com:o/r.git /scm/r"},
+		{&Options{Depth: 1, SingleBranch: true}, "clone --progress --depth=1 --single-branch git@github.com:o/r.git /scm/r"},
+		{&Options{Filter: "blob:none", SparsePaths: []string{"docs"}}, "clone --progress --filter=blob:none --sparse git@github.com:o/r.git /scm/r"},
+	}
+	t.Run("git clone args should reflect the clone options", func(t *testing.T) {
+		for _, tc := range tests {
+			args := strings.Join(gitCloneArgs("git@github.com:o/r.git", "/scm/r", tc.opts), " ")
+			if args != tc.expected {
+				t.Errorf("expecting %s but got %s", tc.expected, args)
+			}
+		}
+	})
+}
+
+func TestCloneUrls(t *testing.T) {
+	var tests = []struct {
+		host         *config.ScmHost
+		expectedSsh  string
+		expectedHttp string
+	}{
+		{&config.ScmHost{Hostname: "github.com", Provider: config.GitHub, Scheme: config.Https}, "git@github.com:o/r.git", "https://github.com/o/r.git"},
+		{&config.ScmHost{Hostname: "git.example.com", Provider: config.GitLab, SshUser: "gerrit", Scheme: config.Http}, "gerrit@git.example.com:o/r.git", "http://git.example.com/o/r.git"},
+		{&config.ScmHost{Hostname: "gitlab.example.com", Provider: config.GitLab, SshPort: 2222, Scheme: config.Https}, "ssh://git@gitlab.example.com:2222/o/r.git", "https://gitlab.example.com/o/r.git"},
+		{&config.ScmHost{
+			Hostname:             "bitbucket.example.com",
+			Provider:             config.GitHub,
+			SshCloneUrlTemplate:  "ssh://{{.SshUser}}@{{.Hostname}}:{{.SshPort}}/{{.RepoPath}}.git",
+			HttpCloneUrlTemplate: "{{.Scheme}}://{{.Hostname}}/scm/{{.RepoPath}}.git",
+		}, "ssh://git@bitbucket.example.com:22/o/r.git", "https://bitbucket.example.com/scm/o/r.git"},
+		{&config.ScmHost{Hostname: "bitbucket.example.com", Provider: config.BitBucketDatacenter, Scheme: config.Https}, "ssh://git@bitbucket.example.com:7999/o/r.git", "https://bitbucket.example.com/scm/o/r.git"},
+	}
+	t.Run("clone urls should use the ssh user, port and templates of the host", func(t *testing.T) {
+		for _, tc := range tests {
+			sshUrl, err := GetSshCloneUrl(tc.host, "o/r")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sshUrl != tc.expectedSsh {
+				t.Errorf("expecting %s but got %s", tc.expectedSsh, sshUrl)
+			}
+			httpUrl, err := GetHttpCloneUrl(tc.host, "o/r")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if httpUrl != tc.expectedHttp {
+				t.Errorf("expecting %s but got %s", tc.expectedHttp, httpUrl)
+			}
+		}
+	})
+	t.Run("azure devops clone urls should use the ssh host and the _git path", func(t *testing.T) {
+		host := &config.ScmHost{Hostname: "dev.azure.com", Provider: config.AzureDevOps, Scheme: config.Https}
+		if sshUrl, _ := GetSshCloneUrl(host, "org/project/repo"); sshUrl != "git@ssh.dev.azure.com:v3/org/project/repo" {
+			t.Errorf("unexpected ssh clone url %s", sshUrl)
+		}
+		if httpUrl, _ := GetHttpCloneUrl(host, "org/project/repo"); httpUrl != "https://dev.azure.com/org/project/_git/repo" {
+			t.Errorf("unexpected http clone url %s", httpUrl)
+		}
+	})
+	t.Run("gerrit ssh clone urls should use port 29418 and leave the user to the ssh config", func(t *testing.T) {
+		if sshUrl, _ := GetSshCloneUrl(&config.ScmHost{Hostname: "review.example.com", Provider: config.Gerrit}, "platform/build"); sshUrl != "ssh://review.example.com:29418/platform/build" {
+			t.Errorf("unexpected ssh clone url %s", sshUrl)
+		}
+		if sshUrl, _ := GetSshCloneUrl(&config.ScmHost{Hostname: "review.example.com", Provider: config.Gerrit, SshUser: "jdoe"}, "platform/build"); sshUrl != "ssh://jdoe@review.example.com:29418/platform/build" {
+			t.Errorf("unexpected ssh clone url %s", sshUrl)
+		}
+	})
+	t.Run("sourcehut clone urls should keep the ~ owner and have no .git suffix", func(t *testing.T) {
+		host := &config.ScmHost{Hostname: "git.sr.ht", Provider: config.SourceHut, Scheme: config.Https}
+		if sshUrl, _ := GetSshCloneUrl(host, "~user/repo"); sshUrl != "git@git.sr.ht:~user/repo" {
+			t.Errorf("unexpected ssh clone url %s", sshUrl)
+		}
+		if httpUrl, _ := GetHttpCloneUrl(host, "~user/repo"); httpUrl != "https://git.sr.ht/~user/repo" {
+			t.Errorf("unexpected http clone url %s", httpUrl)
+		}
+	})
+	t.Run("invalid templates should fail", func(t *testing.T) {
+		if _, err := GetSshCloneUrl(&config.ScmHost{Hostname: "h", Provider: config.GitHub, SshCloneUrlTemplate: "{{.Unknown}}"}, "o/r"); err == nil {
+			t.Errorf("expecting an error for a template with an unknown field")
+		}
+	})
+}
+
+// newTestRepo creates a repository with the files and the given number of commits and returns its path
+func newTestRepo(t *testing.T, files map[string]string, commits int) string {
+	t.Helper()
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	for i := 0; i < commits; i++ {
+		for name, content := range files {
+			p := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+				t.Fatalf("failed to create dir: %v", err)
+			}
+			if err := os.WriteFile(p, []byte(strings.Repeat(content, i+1)), 0644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+			if _, err := wt.Add(name); err != nil {
+				t.Fatalf("failed to add file: %v", err)
+			}
+		}
+		sig := &object.Signature{Name: "gitr", Email: "gitr@example.com", When: time.Now()}
+		if _, err := wt.Commit("commit", &git.CommitOptions{Author: sig}); err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+	}
+	return dir
+}
@@ -0,0 +1,145 @@
+package clone
+
+import (
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	gitrgit "github.com/swarupdonepudi/gitr/pkg/git"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+// ErrDirtyWorktree is returned when an existing checkout has uncommitted changes
+// and can not be switched to the ref of the url
+var ErrDirtyWorktree = errors.New("worktree has uncommitted changes")
+
+// ErrPullRequestDiverged is returned when the local branch of a pull request has commits
+// that are not in the pull request and can not be fast-forwarded to it
+var ErrPullRequestDiverged = errors.New("local branch has diverged from the pull request")
+
+// checkoutRef checks out the branch, tag or pull request of the browser url in a fresh clone.
+// Shallow and single branch clones only track the default branch,
+// so the branches and tags of the remote are fetched, as deep as the clone, to resolve the ref name.
+func checkoutRef(backend Backend, repoLocation string, ref *provider.Ref, opts *Options, chain *auth.Chain) error {
+	return switchRef(backend, repoLocation, ref, chain, opts.Depth, opts.Depth > 0 || opts.SingleBranch)
+}
+
+// switchExistingCheckout switches an existing clone to the branch, tag or pull request of the browser url.
+// Checkouts with uncommitted changes are left untouched.
+func switchExistingCheckout(backend Backend, repoLocation string, ref *provider.Ref, chain *auth.Chain) error {
+	r, err := gogit.PlainOpen(repoLocation)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	clean, err := gitrgit.IsWorktreeClean(r)
+	if err != nil {
+		return err
+	}
+	if !clean {
+		return errors.Wrapf(ErrDirtyWorktree, "refusing to switch %s", repoLocation)
+	}
+	return switchRef(backend, repoLocation, ref, chain, 0, true)
+}
+
+// switchRef checks out the ref with the backend, fetching the branches and tags of the remote first when fetchRefs is set.
+// The refspecs are explicit since the refspec of the remote is narrowed to the default branch by single branch clones.
+// Pull requests are fetched into refs/remotes/<remote>/pr/<number> and their local branch is created or fast-forwarded from there.
+func switchRef(backend Backend, repoLocation string, ref *provider.Ref, chain *auth.Chain, depth int, fetchRefs bool) error {
+	r, err := gogit.PlainOpen(repoLocation)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	remoteName, err := gitrgit.GetRemoteName(r)
+	if err != nil {
+		return err
+	}
+	switch ref.Kind {
+	case provider.RefPullRequest:
+		remoteRef := plumbing.NewRemoteReferenceName(remoteName, fmt.Sprintf("pr/%d", ref.Number))
+		refSpec := fmt.Sprintf("+%s:%s", ref.RemoteRef, remoteRef)
+		if err := backend.Fetch(repoLocation, remoteName, []string{refSpec}, depth, chain); err != nil {
+			return errors.Wrapf(err, "failed to fetch pull request %d", ref.Number)
+		}
+		if err := updatePullRequestBranch(backend, repoLocation, r, ref, remoteRef, chain); err != nil {
+			return err
+		}
+		if _, err := backend.Checkout(repoLocation, []string{ref.LocalBranch}, chain); err != nil {
+			return errors.Wrapf(err, "failed to checkout pull request %d", ref.Number)
+		}
+		ui.CheckedOutRef(fmt.Sprintf("%s (pull request #%d)", ref.LocalBranch, ref.Number))
+	default:
+		if fetchRefs {
+			refSpecs := []string{fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", remoteName), "+refs/tags/*:refs/tags/*"}
+			if err := backend.Fetch(repoLocation, remoteName, refSpecs, depth, chain); err != nil {
+				return errors.Wrap(err, "failed to fetch branches and tags")
+			}
+		}
+		name, err := backend.Checkout(repoLocation, ref.Candidates(), chain)
+		if err != nil {
+			return errors.Wrap(err, "failed to checkout ref from url")
+		}
+		ui.CheckedOutRef(name)
+	}
+	return nil
+}
+
+// updatePullRequestBranch points the local branch of the pull request at the fetched pull request head.
+// The branch is created when missing and fast-forwarded otherwise, through the backend when it is checked out.
+// Branches with commits that are not in the pull request are left untouched.
+func updatePullRequestBranch(backend Backend, repoLocation string, r *gogit.Repository, ref *provider.Ref, remoteRef plumbing.ReferenceName, chain *auth.Chain) error {
+	target, err := r.Reference(remoteRef, true)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve %s", remoteRef)
+	}
+	branchRef := plumbing.NewBranchReferenceName(ref.LocalBranch)
+	local, err := r.Reference(branchRef, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return setBranch(r, branchRef, target.Hash())
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve %s", branchRef)
+	}
+	if local.Hash() == target.Hash() {
+		return nil
+	}
+	localCommit, err := r.CommitObject(local.Hash())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get commit of %s", ref.LocalBranch)
+	}
+	targetCommit, err := r.CommitObject(target.Hash())
+	if err != nil {
+		return errors.Wrapf(err, "failed to get commit of pull request %d", ref.Number)
+	}
+	fastForward, err := localCommit.IsAncestor(targetCommit)
+	if err != nil {
+		return errors.Wrapf(err, "failed to compare %s with pull request %d", ref.LocalBranch, ref.Number)
+	}
+	if !fastForward {
+		return errors.Wrapf(ErrPullRequestDiverged, "%s has commits that are not in pull request %d, rename or delete the branch to check out the pull request",
+			ref.LocalBranch, ref.Number)
+	}
+	if head, err := r.Head(); err == nil && head.Name() == branchRef {
+		return backend.FastForward(repoLocation, target.Hash(), chain)
+	}
+	return setBranch(r, branchRef, target.Hash())
+}
+
+func setBranch(r *gogit.Repository, branchRef plumbing.ReferenceName, hash plumbing.Hash) error {
+	if err := r.Storer.SetReference(plumbing.NewHashReference(branchRef, hash)); err != nil {
+		return errors.Wrapf(err, "failed to point %s at %s", branchRef.Short(), hash)
+	}
+	return nil
+}
+
+// remoteAuth returns the credentials of the auth chain for the transport of the remote of the repo
+func remoteAuth(r *gogit.Repository, chain *auth.Chain) transport.AuthMethod {
+	remoteUrl, err := gitrgit.GetGitRemoteUrl(r)
+	if err != nil {
+		return nil
+	}
+	return chain.Authenticate(auth.TransportOf(remoteUrl)).AuthMethod()
+}
@@ -0,0 +1,253 @@
+package clone
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	gitrgit "github.com/swarupdonepudi/gitr/pkg/git"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+func TestCheckoutRef(t *testing.T) {
+	ui.SetQuiet(true)
+	defer ui.SetQuiet(false)
+	src := newTestRepo(t, map[string]string{"README.md": "readme", "docs/guide.md": "guide"}, 2)
+	srcRepo, err := git.PlainOpen(src)
+	if err != nil {
+		t.Fatalf("failed to open source repo: %v", err)
+	}
+	head, err := srcRepo.Head()
+	if err != nil {
+		t.Fatalf("failed to get head: %v", err)
+	}
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName("feature/x"),
+		"refs/pull/7/head",
+	} {
+		if err := srcRepo.Storer.SetReference(plumbing.NewHashReference(name, head.Hash())); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+	if _, err := srcRepo.CreateTag("v1.0.0", head.Hash(), nil); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+
+	clonePath := filepath.Join(t.TempDir(), "repo")
+	if err := (&goGitBackend{}).Clone(src, clonePath, nil, &Options{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, err := git.PlainOpen(clonePath)
+	if err != nil {
+		t.Fatalf("failed to open clone: %v", err)
+	}
+
+	t.Run("branch names containing slashes should be resolved from blob urls", func(t *testing.T) {
+		if err := checkoutRef(&goGitBackend{}, clonePath, &provider.Ref{Kind: provider.RefName, Name: "feature/x/docs/guide.md"}, &Options{}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if branch, _ := gitrgit.GetGitBranch(r); branch != "feature/x" {
+			t.Errorf("expecting feature/x to be checked out but got %s", branch)
+		}
+	})
+	t.Run("pull request heads should be fetched into a local branch", func(t *testing.T) {
+		ref := &provider.Ref{Kind: provider.RefPullRequest, Number: 7, RemoteRef: "refs/pull/7/head", LocalBranch: "pr-7"}
+		if err := switchExistingCheckout(&goGitBackend{}, clonePath, ref, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if branch, _ := gitrgit.GetGitBranch(r); branch != "pr-7" {
+			t.Errorf("expecting pr-7 to be checked out but got %s", branch)
+		}
+	})
+	t.Run("tags should be checked out", func(t *testing.T) {
+		if err := switchExistingCheckout(&goGitBackend{}, clonePath, &provider.Ref{Kind: provider.RefName, Name: "v1.0.0"}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if h, _ := r.Head(); h.Hash() != head.Hash() {
+			t.Errorf("expecting head at %s but got %s", head.Hash(), h.Hash())
+		}
+	})
+	t.Run("dirty checkouts should not be switched", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(clonePath, "README.md"), []byte("local change"), 0644); err != nil {
+			t.Fatalf("failed to modify file: %v", err)
+		}
+		err := switchExistingCheckout(&goGitBackend{}, clonePath, &provider.Ref{Kind: provider.RefName, Name: "feature/x"}, nil)
+		if !errors.Is(err, ErrDirtyWorktree) {
+			t.Errorf("expecting dirty worktree error but got %v", err)
+		}
+	})
+}
+
+func TestCheckoutRefShallowClone(t *testing.T) {
+	ui.SetQuiet(true)
+	defer ui.SetQuiet(false)
+	src := newTestRepo(t, map[string]string{"README.md": "readme", "docs/guide.md": "guide"}, 2)
+	srcRepo, err := git.PlainOpen(src)
+	if err != nil {
+		t.Fatalf("failed to open source repo: %v", err)
+	}
+	head, err := srcRepo.Head()
+	if err != nil {
+		t.Fatalf("failed to get head: %v", err)
+	}
+	headCommit, err := srcRepo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatalf("failed to get head commit: %v", err)
+	}
+	// the branch points at a commit a depth 1 clone of the default branch does not have
+	featureHash := headCommit.ParentHashes[0]
+	if err := srcRepo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature/x"), featureHash)); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+
+	backends := []Backend{&goGitBackend{}}
+	if _, err := exec.LookPath("git"); err == nil {
+		backends = append(backends, &execBackend{})
+	}
+	for _, backend := range backends {
+		t.Run(string(backend.Name())+" should fetch and check out a non default branch of a depth 1 clone", func(t *testing.T) {
+			clonePath := filepath.Join(t.TempDir(), "repo")
+			opts := &Options{Depth: 1}
+			if err := backend.Clone("file://"+src, clonePath, nil, opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := checkoutRef(backend, clonePath, &provider.Ref{Kind: provider.RefName, Name: "feature/x/docs"}, opts, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			r, err := git.PlainOpen(clonePath)
+			if err != nil {
+				t.Fatalf("failed to open clone: %v", err)
+			}
+			if branch, _ := gitrgit.GetGitBranch(r); branch != "feature/x" {
+				t.Errorf("expecting feature/x to be checked out but got %s", branch)
+			}
+			if h, _ := r.Head(); h.Hash() != featureHash {
+				t.Errorf("expecting head at %s but got %s", featureHash, h.Hash())
+			}
+			if cfg, err := r.Config(); err != nil || cfg.Branches["feature/x"] == nil {
+				t.Errorf("expecting feature/x to track the remote branch")
+			}
+		})
+	}
+}
+
+func TestSwitchPullRequest(t *testing.T) {
+	ui.SetQuiet(true)
+	defer ui.SetQuiet(false)
+	src := newTestRepo(t, map[string]string{"README.md": "readme"}, 2)
+	srcRepo, err := git.PlainOpen(src)
+	if err != nil {
+		t.Fatalf("failed to open source repo: %v", err)
+	}
+	head, err := srcRepo.Head()
+	if err != nil {
+		t.Fatalf("failed to get head: %v", err)
+	}
+	headCommit, err := srcRepo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatalf("failed to get head commit: %v", err)
+	}
+	setPullRequestHead := func(t *testing.T, hash plumbing.Hash) {
+		if err := srcRepo.Storer.SetReference(plumbing.NewHashReference("refs/pull/7/head", hash)); err != nil {
+			t.Fatalf("failed to set pull request head: %v", err)
+		}
+	}
+	ref := &provider.Ref{Kind: provider.RefPullRequest, Number: 7, RemoteRef: "refs/pull/7/head", LocalBranch: "pr-7"}
+
+	backends := []Backend{&goGitBackend{}}
+	if _, err := exec.LookPath("git"); err == nil {
+		backends = append(backends, &execBackend{})
+	}
+	for _, backend := range backends {
+		clonePath := filepath.Join(t.TempDir(), "repo")
+		if err := backend.Clone("file://"+src, clonePath, nil, &Options{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		r, err := git.PlainOpen(clonePath)
+		if err != nil {
+			t.Fatalf("failed to open clone: %v", err)
+		}
+		t.Run(string(backend.Name())+" should fast-forward the checked out branch of an updated pull request", func(t *testing.T) {
+			setPullRequestHead(t, headCommit.ParentHashes[0])
+			if err := switchExistingCheckout(backend, clonePath, ref, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			setPullRequestHead(t, head.Hash())
+			if err := switchExistingCheckout(backend, clonePath, ref, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if branch, _ := gitrgit.GetGitBranch(r); branch != "pr-7" {
+				t.Errorf("expecting pr-7 to be checked out but got %s", branch)
+			}
+			if h, _ := r.Head(); h.Hash() != head.Hash() {
+				t.Errorf("expecting head at %s but got %s", head.Hash(), h.Hash())
+			}
+			if content, _ := os.ReadFile(filepath.Join(clonePath, "README.md")); string(content) != "readmereadme" {
+				t.Errorf("expecting the worktree to be updated but got %q", content)
+			}
+			if remoteRef, err := r.Reference("refs/remotes/origin/pr/7", true); err != nil || remoteRef.Hash() != head.Hash() {
+				t.Errorf("expecting the pull request head to be fetched into refs/remotes/origin/pr/7")
+			}
+		})
+		t.Run(string(backend.Name())+" should refuse to move a local branch that has diverged from the pull request", func(t *testing.T) {
+			wt, err := r.Worktree()
+			if err != nil {
+				t.Fatalf("failed to get worktree: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(clonePath, "NOTES.md"), []byte("notes"), 0644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+			if _, err := wt.Add("NOTES.md"); err != nil {
+				t.Fatalf("failed to add file: %v", err)
+			}
+			local, err := wt.Commit("local commit", &git.CommitOptions{Author: &object.Signature{Name: "gitr", Email: "gitr@example.com", When: time.Now()}})
+			if err != nil {
+				t.Fatalf("failed to commit: %v", err)
+			}
+			setPullRequestHead(t, headCommit.ParentHashes[0])
+			err = switchExistingCheckout(backend, clonePath, ref, nil)
+			if !errors.Is(err, ErrPullRequestDiverged) {
+				t.Errorf("expecting diverged pull request error but got %v", err)
+			}
+			if h, _ := r.Head(); h.Hash() != local {
+				t.Errorf("expecting head to stay at %s but got %s", local, h.Hash())
+			}
+		})
+	}
+}
+
+func TestCloneForkRef(t *testing.T) {
+	cfg := &config.GitrConfig{Scm: &config.Scm{HomeDir: t.TempDir(), Hosts: []*config.ScmHost{{Hostname: "github.com", Provider: config.GitHub, Clone: &config.CloneConfig{}}}}}
+	t.Run("forking a browser url of a branch should be an error", func(t *testing.T) {
+		_, err := CloneFork(cfg, "https://github.com/owner/repo/tree/feature/x", &Options{})
+		if !errors.Is(err, ErrForkRef) {
+			t.Errorf("expecting fork ref error but got %v", err)
+		}
+	})
+}
+
+func TestGetUpstreamUrl(t *testing.T) {
+	var tests = []struct {
+		originUrl string
+		expected  string
+	}{
+		{"git@github.com:jane/kind.git", "git@github.com:kubernetes-sigs/kind.git"},
+		{"https://github.com/jane/kind.git", "https://github.com/kubernetes-sigs/kind.git"},
+		{"https://github.com/jane/kind", "https://github.com/kubernetes-sigs/kind"},
+	}
+	t.Run("upstream url should use the transport of the origin url", func(t *testing.T) {
+		for _, tc := range tests {
+			if got := GetUpstreamUrl(tc.originUrl, "jane/kind", "kubernetes-sigs/kind"); got != tc.expected {
+				t.Errorf("expecting %s but got %s", tc.expected, got)
+			}
+		}
+	})
+}
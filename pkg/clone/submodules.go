@@ -0,0 +1,131 @@
+package clone
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/auth"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	gitrgit "github.com/swarupdonepudi/gitr/pkg/git"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+// updateSubmodules clones the submodules of a fresh clone according to the submodule mode of the options
+func updateSubmodules(cfg *config.GitrConfig, backend Backend, chain *auth.Chain, repoLocation string, opts *Options) error {
+	switch opts.Submodules {
+	case "", config.SubmodulesNone:
+		return nil
+	case config.SubmodulesRecurse:
+		ui.UpdatingSubmodules(repoLocation)
+		return backend.UpdateSubmodules(repoLocation, chain)
+	case config.SubmodulesManaged:
+		execBackend, ok := backend.(*execBackend)
+		if !ok {
+			opts.warn("Managed Submodules Not Supported",
+				fmt.Sprintf("The %s clone backend can not link submodules to their gitr paths, submodules are cloned in place.", backend.Name()))
+			ui.UpdatingSubmodules(repoLocation)
+			return backend.UpdateSubmodules(repoLocation, chain)
+		}
+		return linkManagedSubmodules(cfg, execBackend, chain, repoLocation, opts)
+	}
+	return errors.Errorf("unknown submodules mode %s, expecting one of %s, %s or %s", opts.Submodules,
+		config.SubmodulesNone, config.SubmodulesRecurse, config.SubmodulesManaged)
+}
+
+// linkManagedSubmodules clones the remote of every submodule to its own gitr path
+// and checks out the submodule with that clone as a git alternate,
+// so that a submodule shared by many repos is downloaded only once.
+// Submodules of hosts unknown to gitr are cloned in place.
+func linkManagedSubmodules(cfg *config.GitrConfig, backend *execBackend, chain *auth.Chain, repoLocation string, opts *Options) error {
+	r, err := gogit.PlainOpen(repoLocation)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open repo %s", repoLocation)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "failed to get worktree")
+	}
+	subs, err := wt.Submodules()
+	if err != nil {
+		return errors.Wrap(err, "failed to read submodules")
+	}
+	originUrl := getOriginUrl(repoLocation)
+	for _, sub := range subs {
+		subCfg := sub.Config()
+		subUrl := ResolveSubmoduleUrl(originUrl, subCfg.URL)
+		subOpts := &Options{
+			Token:         opts.Token,
+			CreDir:        opts.CreDir,
+			OnExistingDir: opts.OnExistingDir,
+			Submodules:    config.SubmodulesManaged,
+			warnings:      opts.warnings,
+		}
+		referencePath := ""
+		if auth.TransportOf(subUrl) != "" {
+			referencePath, err = Clone(cfg, subUrl, subOpts)
+		}
+		if err != nil || referencePath == "" {
+			log.Debugf("cloning submodule %s in place since it could not be cloned to its gitr path: %v", subCfg.Path, err)
+			if err := backend.run(originUrl, gitEnv(originUrl, chain),
+				"-C", repoLocation, "submodule", "update", "--init", "--recursive", "--progress", "--", subCfg.Path); err != nil {
+				return errors.Wrapf(err, "failed to update submodule %s", subCfg.Path)
+			}
+			continue
+		}
+		if err := backend.updateSubmoduleWithReference(repoLocation, subCfg.Path, referencePath, chain); err != nil {
+			return errors.Wrapf(err, "failed to update submodule %s", subCfg.Path)
+		}
+		ui.LinkedSubmodule(subCfg.Path, referencePath)
+		if err := linkManagedSubmodules(cfg, backend, chain, filepath.Join(repoLocation, subCfg.Path), opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveSubmoduleUrl resolves a submodule url relative to the url of the superproject, like git does.
+// Urls that do not start with ./ or ../ are returned as is.
+func ResolveSubmoduleUrl(superprojectUrl, submoduleUrl string) string {
+	if !strings.HasPrefix(submoduleUrl, "./") && !strings.HasPrefix(submoduleUrl, "../") {
+		return submoduleUrl
+	}
+	base := strings.TrimSuffix(superprojectUrl, "/")
+	sep := "/"
+	rel := submoduleUrl
+	for {
+		if strings.HasPrefix(rel, "./") {
+			rel = strings.TrimPrefix(rel, "./")
+			continue
+		}
+		if strings.HasPrefix(rel, "../") {
+			rel = strings.TrimPrefix(rel, "../")
+			// the last path element of the base is dropped, which is the host path separator of scp-like urls
+			idx := strings.LastIndexAny(base, "/:")
+			if idx == -1 {
+				break
+			}
+			sep = string(base[idx])
+			base = base[:idx]
+			continue
+		}
+		break
+	}
+	return base + sep + rel
+}
+
+// getOriginUrl returns the url of the remote of the repo, or an empty string when it can not be read
+func getOriginUrl(repoLocation string) string {
+	r, err := gogit.PlainOpen(repoLocation)
+	if err != nil {
+		return ""
+	}
+	remoteUrl, err := gitrgit.GetGitRemoteUrl(r)
+	if err != nil {
+		return ""
+	}
+	return remoteUrl
+}
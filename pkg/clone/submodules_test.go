@@ -0,0 +1,84 @@
+package clone
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/ui"
+)
+
+func TestResolveSubmoduleUrl(t *testing.T) {
+	var tests = []struct {
+		superprojectUrl string
+		submoduleUrl    string
+		expected        string
+	}{
+		{"https://github.com/o/app.git", "../lib.git", "https://github.com/o/lib.git"},
+		{"https://github.com/o/app.git", "../../other/lib.git", "https://github.com/other/lib.git"},
+		{"https://github.com/o/app.git", "./lib", "https://github.com/o/app.git/lib"},
+		{"git@github.com:o/app.git", "../lib.git", "git@github.com:o/lib.git"},
+		{"git@github.com:o/app.git", "../../other/lib.git", "git@github.com:other/lib.git"},
+		{"git@github.com:o/app.git", "https://gitlab.com/g/lib.git", "https://gitlab.com/g/lib.git"},
+	}
+	t.Run("relative submodule urls should be resolved against the superproject url", func(t *testing.T) {
+		for _, tc := range tests {
+			if got := ResolveSubmoduleUrl(tc.superprojectUrl, tc.submoduleUrl); got != tc.expected {
+				t.Errorf("expecting %s but got %s", tc.expected, got)
+			}
+		}
+	})
+}
+
+func TestBackendUpdateSubmodules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required to add submodules")
+	}
+	ui.SetQuiet(true)
+	defer ui.SetQuiet(false)
+	// local submodule urls are blocked by default since git 2.38
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	lib := newTestRepo(t, map[string]string{"lib.go": "package lib"}, 1)
+	super := newTestRepo(t, map[string]string{"README.md": "readme"}, 1)
+	for _, args := range [][]string{
+		{"-C", super, "submodule", "add", lib, "vendor/lib"},
+		{"-C", super, "-c", "user.name=gitr", "-c", "user.email=gitr@example.com", "commit", "-m", "add lib"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("failed to set up submodule: %v: %s", err, out)
+		}
+	}
+
+	for _, backend := range []Backend{&goGitBackend{}, &execBackend{}} {
+		t.Run(string(backend.Name())+" should check out submodules", func(t *testing.T) {
+			clonePath := filepath.Join(t.TempDir(), "repo")
+			if err := backend.Clone(super, clonePath, nil, &Options{}); err != nil {
+				t.Fatalf("unexpected clone error: %v", err)
+			}
+			if err := backend.UpdateSubmodules(clonePath, nil); err != nil {
+				t.Fatalf("unexpected submodule error: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(clonePath, "vendor", "lib", "lib.go")); err != nil {
+				t.Errorf("expecting submodule to be checked out: %v", err)
+			}
+		})
+	}
+	t.Run("managed mode should check out submodules without a gitr path in place", func(t *testing.T) {
+		backend := &execBackend{}
+		clonePath := filepath.Join(t.TempDir(), "repo")
+		if err := backend.Clone(super, clonePath, nil, &Options{}); err != nil {
+			t.Fatalf("unexpected clone error: %v", err)
+		}
+		if err := updateSubmodules(nil, backend, nil, clonePath, &Options{Submodules: config.SubmodulesManaged}); err != nil {
+			t.Fatalf("unexpected submodule error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(clonePath, "vendor", "lib", "lib.go")); err != nil {
+			t.Errorf("expecting submodule to be checked out: %v", err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+// Package clonerr classifies clone failures into errors that callers can tell apart with errors.Is
+package clonerr
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+var (
+	ErrRepoNotFound    = errors.New("repository not found")
+	ErrAuthFailed      = errors.New("authentication failed")
+	ErrHostUnreachable = errors.New("host unreachable")
+	ErrHostKeyMismatch = errors.New("ssh host key mismatch")
+	ErrDiskFull        = errors.New("no space left on device")
+)
+
+// gitFatalExitCode is the exit code of git for every fatal error, the cause is only found in stderr
+const gitFatalExitCode = 128
+
+// outputPatterns maps the messages of git, ssh and scm hosts to the errors they indicate.
+// Host key and disk errors come first since they are also reported as failed connections or clones.
+var outputPatterns = []struct {
+	err      error
+	patterns []*regexp.Regexp
+}{
+	{ErrHostKeyMismatch, compile(
+		`host key verification failed`,
+		`remote host identification has changed`,
+		`knownhosts: key mismatch`,
+	)},
+	{ErrDiskFull, compile(
+		`no space left on device`,
+		`disk quota exceeded`,
+	)},
+	{ErrRepoNotFound, compile(
+		`repository not found`,
+		`repo not found`,
+		`project not found`,
+		`the project you were looking for could not be found`, // GitLab
+		`does not appear to be a git repository`,
+		`repository '[^']*' not found`,
+	)},
+	{ErrAuthFailed, compile(
+		`authentication failed`,
+		`permission denied \(publickey`,
+		`could not read (username|password)`,
+		`invalid username or password`,
+		`http basic: access denied`,
+		`the requested url returned error: 40[13]`,
+		`unable to authenticate`,
+	)},
+	{ErrHostUnreachable, compile(
+		`could not resolve host`,
+		`connection refused`,
+		`connection timed out`,
+		`operation timed out`,
+		`network is unreachable`,
+		`no route to host`,
+		`failed to connect to`,
+	)},
+}
+
+func compile(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		compiled = append(compiled, regexp.MustCompile(p))
+	}
+	return compiled
+}
+
+// FromGitExit classifies a failed git command from its exit code and stderr.
+// nil is returned when the cause is unknown.
+func FromGitExit(exitCode int, stderr string) error {
+	if exitCode != gitFatalExitCode {
+		return nil
+	}
+	return FromOutput(stderr)
+}
+
+// FromGoGit classifies a go-git error using its transport errors and falls back to the error message.
+// nil is returned when the cause is unknown.
+func FromGoGit(err error) error {
+	if err == nil {
+		return nil
+	}
+	var keyErr *knownhosts.KeyError
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &keyErr) && len(keyErr.Want) > 0:
+		return ErrHostKeyMismatch
+	case errors.Is(err, syscall.ENOSPC):
+		return ErrDiskFull
+	case errors.Is(err, transport.ErrRepositoryNotFound):
+		return ErrRepoNotFound
+	case errors.Is(err, transport.ErrAuthenticationRequired), errors.Is(err, transport.ErrAuthorizationFailed):
+		return ErrAuthFailed
+	case errors.As(err, &dnsErr), errors.As(err, &opErr):
+		return ErrHostUnreachable
+	}
+	return FromOutput(err.Error())
+}
+
+// FromOutput classifies a failure from the messages printed by git, ssh or the scm host.
+// nil is returned when the cause is unknown.
+func FromOutput(output string) error {
+	output = strings.ToLower(output)
+	for _, o := range outputPatterns {
+		for _, p := range o.patterns {
+			if p.MatchString(output) {
+				return o.err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package clonerr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+func TestFromGitExit(t *testing.T) {
+	var tests = []struct {
+		exitCode int
+		stderr   string
+		expected error
+	}{
+		{128, "ERROR: Repository not found.\nfatal: Could not read from remote repository.", ErrRepoNotFound},
+		{128, "fatal: repository 'https://git.example.com/o/r.git/' not found", ErrRepoNotFound},
+		{128, "git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", ErrAuthFailed},
+		{128, "fatal: could not read Username for 'https://github.com': terminal prompts disabled", ErrAuthFailed},
+		{128, "remote: HTTP Basic: Access denied\nfatal: Authentication failed for 'https://gitlab.com/o/r.git/'", ErrAuthFailed},
+		{128, "ssh: Could not resolve hostname gihtub.com: Name or service not known", ErrHostUnreachable},
+		{128, "fatal: unable to access 'https://git.example.com/o/r.git/': Failed to connect to git.example.com port 443", ErrHostUnreachable},
+		{128, "@@@ WARNING: REMOTE HOST IDENTIFICATION HAS CHANGED! @@@\nHost key verification failed.", ErrHostKeyMismatch},
+		{128, "fatal: write error: No space left on device", ErrDiskFull},
+		{128, "fatal: the remote end hung up unexpectedly", nil},
+		{1, "error: repository not found", nil},
+	}
+	t.Run("git failures should be classified from stderr", func(t *testing.T) {
+		for _, tc := range tests {
+			if got := FromGitExit(tc.exitCode, tc.stderr); got != tc.expected {
+				t.Errorf("expecting %v for %q but got %v", tc.expected, tc.stderr, got)
+			}
+		}
+	})
+}
+
+func TestFromGoGit(t *testing.T) {
+	var tests = []struct {
+		err      error
+		expected error
+	}{
+		{transport.ErrRepositoryNotFound, ErrRepoNotFound},
+		{fmt.Errorf("clone: %w", transport.ErrAuthenticationRequired), ErrAuthFailed},
+		{transport.ErrAuthorizationFailed, ErrAuthFailed},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "gihtub.com"}}, ErrHostUnreachable},
+		{fmt.Errorf("write objects: %w", syscall.ENOSPC), ErrDiskFull},
+		{errors.New("ssh: handshake failed: ssh: unable to authenticate, attempted methods [none publickey]"), ErrAuthFailed},
+		{errors.New("object not found"), nil},
+	}
+	t.Run("go-git errors should be classified from their type", func(t *testing.T) {
+		for _, tc := range tests {
+			if got := FromGoGit(tc.err); got != tc.expected {
+				t.Errorf("expecting %v for %v but got %v", tc.expected, tc.err, got)
+			}
+		}
+	})
+}
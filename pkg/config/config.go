@@ -1,42 +1,143 @@
 package config
 
 import (
-	"fmt"
 	"github.com/leftbin/go-util/pkg/file"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type ScmProvider string
 
 type HttpScheme string
 
+type ExistingDirPolicy string
+
+type CloneBackend string
+
+type SubmoduleMode string
+
+type LfsMode string
+
+type GerritBrowser string
+
 const (
 	GitHub              ScmProvider = "github"
 	GitLab              ScmProvider = "gitlab"
 	BitBucketCloud      ScmProvider = "bitbucket-cloud"
 	BitBucketDatacenter ScmProvider = "bitbucket"
+	Gitea               ScmProvider = "gitea"
+	AzureDevOps         ScmProvider = "azure-devops"
+	Gerrit              ScmProvider = "gerrit"
+	SourceHut           ScmProvider = "sourcehut"
 	Http                HttpScheme  = "http"
 	Https               HttpScheme  = "https"
 )
 
-func EnsureInitialConfig() error {
+const (
+	ExistingDirFail      ExistingDirPolicy = "fail"
+	ExistingDirBackup    ExistingDirPolicy = "backup"
+	ExistingDirPrompt    ExistingDirPolicy = "prompt"
+	ExistingDirOverwrite ExistingDirPolicy = "overwrite"
+)
+
+const (
+	CloneBackendExec  CloneBackend = "exec"
+	CloneBackendGoGit CloneBackend = "gogit"
+	CloneBackendAuto  CloneBackend = "auto"
+)
+
+const (
+	SubmodulesNone    SubmoduleMode = "none"
+	SubmodulesRecurse SubmoduleMode = "recurse"
+	SubmodulesManaged SubmoduleMode = "managed"
+)
+
+const (
+	LfsPull LfsMode = "pull"
+	LfsSkip LfsMode = "skip"
+)
+
+const (
+	GerritBrowserGitiles GerritBrowser = "gitiles"
+	GerritBrowserGitweb  GerritBrowser = "gitweb"
+)
+
+// DefaultSshUser is the user of ssh clone urls for hosts without an sshUser
+const DefaultSshUser = "git"
+
+// DefaultAliases are the host aliases available without configuration, aliases in the config file take precedence
+var DefaultAliases = map[string]string{
+	"gh": "github.com",
+	"gl": "gitlab.com",
+	"bb": "bitbucket.org",
+}
+
+// LoadHook is run on every config loaded by NewGitrConfig, packages that act on parts of the config register one
+type LoadHook func(cfg *GitrConfig) error
+
+var loadHooks []LoadHook
+
+// AddLoadHook registers a hook run on every config loaded by NewGitrConfig
+func AddLoadHook(h LoadHook) {
+	loadHooks = append(loadHooks, h)
+}
+
+// EnvConfigPath is the env var with the path of the config file
+const EnvConfigPath = "GITR_CONFIG"
+
+// configPath is the path of the config file set with the --config flag
+var configPath string
+
+// SetConfigPath sets the path of the config file, taking precedence over the env var and the default locations
+func SetConfigPath(p string) {
+	configPath = p
+}
+
+// GetConfigPath returns the path of the config file. In order of precedence it is the path set with SetConfigPath,
+// the GITR_CONFIG env var, $XDG_CONFIG_HOME/gitr/config.yaml when it exists and ${HOME}/.gitr.yaml
+func GetConfigPath() (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	if p := os.Getenv(EnvConfigPath); p != "" {
+		return p, nil
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return errors.Wrap(err, "failed to get user home dir")
+		return "", errors.Wrap(err, "failed to get user home dir")
+	}
+	xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfigHome == "" {
+		xdgConfigHome = filepath.Join(homeDir, ".config")
+	}
+	if xdgConfigFile := filepath.Join(xdgConfigHome, "gitr", "config.yaml"); file.IsFileExists(xdgConfigFile) {
+		return xdgConfigFile, nil
+	}
+	return filepath.Join(homeDir, ".gitr.yaml"), nil
+}
+
+// EnsureInitialConfig writes the default config to the config file when it does not exist
+func EnsureInitialConfig() error {
+	gitrConfigFile, err := GetConfigPath()
+	if err != nil {
+		return err
+	}
+	if file.IsFileExists(gitrConfigFile) {
+		return nil
 	}
-	gitrConfigFile := fmt.Sprintf("%s/.gitr.yaml", homeDir)
 	cfg := NewDefaultConfig()
 	d, err := yaml.Marshal(&cfg)
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal config")
 	}
-	if !file.IsFileExists(gitrConfigFile) {
-		if err = os.WriteFile(gitrConfigFile, d, 0644); err != nil {
-			return errors.Wrapf(err, "failed to write file %s", gitrConfigFile)
-		}
+	if err := os.MkdirAll(filepath.Dir(gitrConfigFile), os.ModePerm); err != nil {
+		return errors.Wrapf(err, "failed to create dir of %s", gitrConfigFile)
+	}
+	if err = os.WriteFile(gitrConfigFile, d, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write file %s", gitrConfigFile)
 	}
 	return nil
 }
@@ -51,26 +152,43 @@ func GetScmHost(cfg *GitrConfig, hostname string) (*ScmHost, error) {
 	return nil, &UnknownScmHostErr{ScmHost: hostname}
 }
 
+// GetAliasHostname returns the hostname of the host alias, looking up the aliases in the config before the default aliases
+func GetAliasHostname(cfg *GitrConfig, alias string) (string, bool) {
+	if hostname, ok := cfg.Aliases[alias]; ok {
+		return hostname, true
+	}
+	hostname, ok := DefaultAliases[alias]
+	return hostname, ok
+}
+
+// NewGitrConfig returns the effective config: the config file merged with the workspace config file and the GITR_* env vars
 func NewGitrConfig() (*GitrConfig, error) {
-	homeDir, err := os.UserHomeDir()
+	e, err := NewEffectiveConfig()
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read user home directory required for reading ${HOME}/.gitr.yaml")
+		return nil, err
 	}
-	gitrConfigYaml := filepath.Join(homeDir, ".gitr.yaml")
-	file, err := os.ReadFile(gitrConfigYaml)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read %s file", gitrConfigYaml)
+	for _, h := range loadHooks {
+		if err := h(e.Config); err != nil {
+			return nil, errors.Wrapf(err, "invalid config %s", strings.Join(e.Files, ", "))
+		}
 	}
-	var cfg GitrConfig
-	if err := yaml.Unmarshal(file, &cfg); err != nil {
-		return nil, errors.Wrapf(err, "failed to unmarshal %s file", gitrConfigYaml)
+	return e.Config, nil
+}
+
+// ReadConfigFile returns the config of the config file alone, without the workspace config file and the env vars
+func ReadConfigFile() (*GitrConfig, string, error) {
+	gitrConfigYaml, err := GetConfigPath()
+	if err != nil {
+		return nil, "", err
 	}
-	return &cfg, nil
+	_, cfg, err := readConfigFile(gitrConfigYaml, nil)
+	return cfg, gitrConfigYaml, err
 }
 
 func NewDefaultConfig() *GitrConfig {
 	return &GitrConfig{
 		CopyRepoPathCdCmdToClipboard: false,
+		DefaultHost:                  "github.com",
 		Scm: &Scm{
 			HomeDir: "",
 			Hosts:   defaultScmSystems(),
@@ -88,5 +206,7 @@ func defaultScmSystems() []*ScmHost {
 		{Scheme: Https, Hostname: "github.com", Provider: GitHub, DefaultBranch: "master", Clone: defaultCloneConfig},
 		{Scheme: Https, Hostname: "gitlab.com", Provider: GitLab, DefaultBranch: "main", Clone: defaultCloneConfig},
 		{Scheme: Https, Hostname: "bitbucket.org", Provider: BitBucketCloud, DefaultBranch: "master", Clone: defaultCloneConfig},
+		{Scheme: Https, Hostname: "dev.azure.com", Provider: AzureDevOps, DefaultBranch: "main", Clone: defaultCloneConfig},
+		{Scheme: Https, Hostname: "git.sr.ht", Provider: SourceHut, DefaultBranch: "main", Clone: defaultCloneConfig},
 	}
 }
@@ -1,8 +1,11 @@
 package config_test
 
 import (
-	"github.com/swarupdonepudi/gitr/pkg/config"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
 )
 
 func TestGitrConfig(t *testing.T) {
@@ -54,3 +57,63 @@ func TestGitrConfig(t *testing.T) {
 		}
 	})
 }
+
+func TestGetConfigPath(t *testing.T) {
+	home := t.TempDir()
+	xdgConfigHome := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+	t.Setenv(config.EnvConfigPath, "")
+
+	expectPath := func(t *testing.T, expected string) {
+		t.Helper()
+		p, err := config.GetConfigPath()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p != expected {
+			t.Errorf("expecting %s but got %s", expected, p)
+		}
+	}
+	t.Run("config in the home dir should be the default", func(t *testing.T) {
+		expectPath(t, filepath.Join(home, ".gitr.yaml"))
+	})
+	t.Run("existing xdg config should take precedence over the home dir", func(t *testing.T) {
+		xdgConfigFile := filepath.Join(xdgConfigHome, "gitr", "config.yaml")
+		if err := os.MkdirAll(filepath.Dir(xdgConfigFile), os.ModePerm); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(xdgConfigFile, []byte("scm: {}\n"), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		expectPath(t, xdgConfigFile)
+	})
+	t.Run("env var should take precedence over the xdg config", func(t *testing.T) {
+		t.Setenv(config.EnvConfigPath, "/dotfiles/gitr.yaml")
+		expectPath(t, "/dotfiles/gitr.yaml")
+	})
+	t.Run("config path set by the flag should take precedence over the env var", func(t *testing.T) {
+		t.Setenv(config.EnvConfigPath, "/dotfiles/gitr.yaml")
+		config.SetConfigPath("/tmp/gitr.yaml")
+		defer config.SetConfigPath("")
+		expectPath(t, "/tmp/gitr.yaml")
+	})
+}
+
+func TestEnsureInitialConfig(t *testing.T) {
+	gitrConfigFile := filepath.Join(t.TempDir(), "dotfiles", "gitr.yaml")
+	t.Setenv(config.EnvConfigPath, gitrConfigFile)
+	t.Chdir(t.TempDir())
+	t.Run("default config should be written to the config path and loaded from it", func(t *testing.T) {
+		if err := config.EnsureInitialConfig(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cfg, err := config.NewGitrConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := config.GetScmHost(cfg, "github.com"); err != nil {
+			t.Errorf("expecting the default hosts in %s: %v", gitrConfigFile, err)
+		}
+	})
+}
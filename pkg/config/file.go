@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent is the indent of the config files written by gitr
+const defaultIndent = 4
+
+// ConfigFile is a config file edited through its yaml nodes, so that the comments and the order of the keys survive edits
+type ConfigFile struct {
+	Path   string
+	root   yaml.Node
+	indent int
+}
+
+// LoadConfigFile reads the config file at the path for editing
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s file", path)
+	}
+	f := &ConfigFile{Path: path, indent: detectIndent(data)}
+	if err := yaml.Unmarshal(data, &f.root); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s file", path)
+	}
+	if len(f.root.Content) == 0 || f.root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.Errorf("%s is not a gitr config file", path)
+	}
+	return f, nil
+}
+
+// detectIndent returns the indent of the first indented line of the yaml
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || len(trimmed) == len(line) {
+			continue
+		}
+		if indent := len(line) - len(trimmed); indent >= 2 {
+			return indent
+		}
+	}
+	return defaultIndent
+}
+
+// Save validates the edited config and writes it to the config file, nothing is written when the config has problems
+func (f *ConfigFile) Save() error {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(f.indent)
+	if err := enc.Encode(&f.root); err != nil {
+		return errors.Wrap(err, "failed to marshal config")
+	}
+	if err := enc.Close(); err != nil {
+		return errors.Wrap(err, "failed to marshal config")
+	}
+	if problems := Validate(b.Bytes()); len(problems) > 0 {
+		return &InvalidConfigErr{Path: f.Path, Problems: problems}
+	}
+	if err := os.WriteFile(f.Path, b.Bytes(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write file %s", f.Path)
+	}
+	return nil
+}
+
+// AddHost appends the host to the hosts of the config
+func (f *ConfigFile) AddHost(s *ScmHost) error {
+	if _, _, ok := f.findHost(s.Hostname); ok {
+		return errors.Errorf("host %s already exists", s.Hostname)
+	}
+	var hostNode yaml.Node
+	if err := hostNode.Encode(s); err != nil {
+		return errors.Wrapf(err, "failed to marshal host %s", s.Hostname)
+	}
+	scmNode := setMappingValue(f.root.Content[0], "scm", &yaml.Node{Kind: yaml.MappingNode}, false)
+	hostsNode := setMappingValue(scmNode, "hosts", &yaml.Node{Kind: yaml.SequenceNode}, false)
+	// hosts: [] is written in flow style, hosts are written as a block
+	hostsNode.Style = 0
+	hostsNode.Content = append(hostsNode.Content, &hostNode)
+	return nil
+}
+
+// RemoveHost removes the host from the hosts of the config
+func (f *ConfigFile) RemoveHost(hostname string) error {
+	hostsNode, i, ok := f.findHost(hostname)
+	if !ok {
+		return &UnknownScmHostErr{ScmHost: hostname}
+	}
+	hostsNode.Content = append(hostsNode.Content[:i], hostsNode.Content[i+1:]...)
+	return nil
+}
+
+// SetHostValue sets the value of the dot separated key of the host, ex: clone.alwaysCreDir
+func (f *ConfigFile) SetHostValue(hostname, key string, value interface{}) error {
+	hostsNode, i, ok := f.findHost(hostname)
+	if !ok {
+		return &UnknownScmHostErr{ScmHost: hostname}
+	}
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return errors.Wrapf(err, "failed to marshal value of %s", key)
+	}
+	n := hostsNode.Content[i]
+	keys := strings.Split(key, ".")
+	for _, k := range keys[:len(keys)-1] {
+		n = setMappingValue(n, k, &yaml.Node{Kind: yaml.MappingNode}, false)
+	}
+	setMappingValue(n, keys[len(keys)-1], &valueNode, true)
+	return nil
+}
+
+// findHost returns the hosts sequence node and the index of the host in it
+func (f *ConfigFile) findHost(hostname string) (*yaml.Node, int, bool) {
+	_, scmNode := mappingValue(f.root.Content[0], "scm")
+	_, hostsNode := mappingValue(scmNode, "hosts")
+	if hostsNode == nil || hostsNode.Kind != yaml.SequenceNode {
+		return nil, 0, false
+	}
+	for i, hostNode := range hostsNode.Content {
+		if _, hostnameNode := mappingValue(hostNode, "hostname"); hostnameNode != nil && hostnameNode.Value == hostname {
+			return hostsNode, i, true
+		}
+	}
+	return nil, 0, false
+}
+
+// setMappingValue returns the value of the key of the mapping node, setting it to value when the key is not set,
+// is not of the kind of value, or when replace is true. The comments of a replaced value are kept.
+func setMappingValue(n *yaml.Node, key string, value *yaml.Node, replace bool) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		*n = yaml.Node{Kind: yaml.MappingNode, HeadComment: n.HeadComment, LineComment: n.LineComment, FootComment: n.FootComment}
+	}
+	keyNode, existing := mappingValue(n, key)
+	if keyNode == nil {
+		n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+		return value
+	}
+	if !replace && existing.Kind == value.Kind {
+		return existing
+	}
+	value.HeadComment, value.LineComment, value.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+	*existing = *value
+	return existing
+}
@@ -0,0 +1,140 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+const commentedConfig = `# gitr config, kept in the dotfiles repo
+scm:
+  homeDir: ""
+  hosts:
+    # public github
+    - hostname: github.com
+      provider: github # the default host
+      scheme: https
+      clone:
+        alwaysCreDir: true
+`
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "gitr.yaml")
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return p
+}
+
+func editConfigFile(t *testing.T, p string, edit func(f *config.ConfigFile) error) string {
+	t.Helper()
+	f, err := config.LoadConfigFile(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := edit(f); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	return string(data)
+}
+
+func TestConfigFileHosts(t *testing.T) {
+	t.Run("added hosts should be appended and keep the comments and indent of the file", func(t *testing.T) {
+		p := writeConfigFile(t, commentedConfig)
+		data := editConfigFile(t, p, func(f *config.ConfigFile) error {
+			return f.AddHost(&config.ScmHost{Hostname: "gitlab.corp.net", Provider: config.GitLab, Scheme: config.Https,
+				Clone: &config.CloneConfig{AlwaysCreDir: true}})
+		})
+		for _, expected := range []string{"# gitr config, kept in the dotfiles repo", "# public github", "provider: github # the default host", "\n    - hostname: gitlab.corp.net\n      provider: gitlab\n"} {
+			if !strings.Contains(data, expected) {
+				t.Errorf("expecting %q in\n%s", expected, data)
+			}
+		}
+		if strings.Index(data, "github.com") > strings.Index(data, "gitlab.corp.net") {
+			t.Errorf("expecting the new host after the existing ones in\n%s", data)
+		}
+	})
+	t.Run("existing hosts should not be added again", func(t *testing.T) {
+		f, _ := config.LoadConfigFile(writeConfigFile(t, commentedConfig))
+		if err := f.AddHost(&config.ScmHost{Hostname: "github.com", Provider: config.GitHub}); err == nil {
+			t.Errorf("expecting an error for a host that already exists")
+		}
+	})
+	t.Run("set values should replace existing keys and create missing ones", func(t *testing.T) {
+		p := writeConfigFile(t, commentedConfig)
+		data := editConfigFile(t, p, func(f *config.ConfigFile) error {
+			if err := f.SetHostValue("github.com", "scheme", "http"); err != nil {
+				return err
+			}
+			if err := f.SetHostValue("github.com", "sshPort", 2222); err != nil {
+				return err
+			}
+			return f.SetHostValue("github.com", "clone.includeHostForCreDir", true)
+		})
+		cfg := loadConfigFile(t, p)
+		s, _ := config.GetScmHost(cfg, "github.com")
+		if s.Scheme != config.Http || s.SshPort != 2222 || !s.Clone.IncludeHostForCreDir || !s.Clone.AlwaysCreDir {
+			t.Errorf("unexpected host %+v, %+v", s, s.Clone)
+		}
+		if !strings.Contains(data, "provider: github # the default host") {
+			t.Errorf("expecting the comments to be kept in\n%s", data)
+		}
+	})
+	t.Run("removed hosts should be dropped", func(t *testing.T) {
+		p := writeConfigFile(t, commentedConfig)
+		editConfigFile(t, p, func(f *config.ConfigFile) error {
+			if err := f.AddHost(&config.ScmHost{Hostname: "gitlab.corp.net", Provider: config.GitLab, Clone: &config.CloneConfig{}}); err != nil {
+				return err
+			}
+			return f.RemoveHost("github.com")
+		})
+		cfg := loadConfigFile(t, p)
+		if len(cfg.Scm.Hosts) != 1 || cfg.Scm.Hosts[0].Hostname != "gitlab.corp.net" {
+			t.Errorf("unexpected hosts %v", cfg.Scm.Hosts)
+		}
+	})
+	t.Run("edits of unknown hosts should fail", func(t *testing.T) {
+		f, _ := config.LoadConfigFile(writeConfigFile(t, commentedConfig))
+		if err := f.RemoveHost("unknown.example.com"); err == nil {
+			t.Errorf("expecting an error for an unknown host")
+		}
+		if err := f.SetHostValue("unknown.example.com", "scheme", "http"); err == nil {
+			t.Errorf("expecting an error for an unknown host")
+		}
+	})
+	t.Run("edits leaving problems in the config should not be saved", func(t *testing.T) {
+		p := writeConfigFile(t, commentedConfig)
+		f, _ := config.LoadConfigFile(p)
+		if err := f.SetHostValue("github.com", "provider", "githb"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := f.Save().(*config.InvalidConfigErr); !ok {
+			t.Errorf("expecting an invalid config error")
+		}
+		if data, _ := os.ReadFile(p); string(data) != commentedConfig {
+			t.Errorf("expecting the config file to be left untouched but got\n%s", data)
+		}
+	})
+}
+
+func loadConfigFile(t *testing.T, p string) *config.GitrConfig {
+	t.Helper()
+	t.Setenv(config.EnvConfigPath, p)
+	t.Chdir(t.TempDir())
+	cfg, err := config.NewGitrConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return cfg
+}
@@ -0,0 +1,324 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkspaceConfigFile is the name of the config file found by walking up from the current dir to the home dir of
+// the config file, ex: one at the root of scm.homeDir with the hosts shared by a team. Files inside git repos are
+// never read, and the credentialKeys of hosts can not be set. Its values take precedence over the config file.
+const WorkspaceConfigFile = ".gitr.yaml"
+
+// envKeys are the only keys of the config set by env vars, other GITR_* env vars are not config overrides.
+// Env vars take precedence over the config files and their values are validated like the values of a file.
+var envKeys = []struct {
+	env string
+	key string
+}{
+	{"GITR_DEFAULT_HOST", "defaultHost"},
+	{"GITR_SCM_HOME_DIR", "scm.homeDir"},
+	{"GITR_COPY_REPO_PATH_CD_CMD_TO_CLIPBOARD", "copyRepoPathCdCmdToClipboard"},
+}
+
+// mergeKeys are the keys of the items of the lists merged item by item, other lists are replaced as a whole
+var mergeKeys = map[string]string{
+	"scm.hosts": "hostname",
+	"providers": "name",
+}
+
+// EffectiveConfig is the config file merged with the workspace config file and the env vars
+type EffectiveConfig struct {
+	Config *GitrConfig
+	// Files are the config files merged, from the lowest to the highest precedence
+	Files []string
+	// Env are the env vars merged
+	Env []string
+	// origins maps the keys of the values of the config to where they came from, ex: scm.hosts[github.com].scheme
+	origins map[string]string
+	root    *yaml.Node
+}
+
+// Entry is a value of the effective config and where it came from
+type Entry struct {
+	Key    string
+	Value  string
+	Origin string
+}
+
+// NewEffectiveConfig loads the config file and merges the workspace config file found by walking up from the current
+// dir and the GITR_* env vars over it
+func NewEffectiveConfig() (*EffectiveConfig, error) {
+	gitrConfigYaml, err := GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get current dir")
+	}
+	return loadEffectiveConfig(gitrConfigYaml, wd)
+}
+
+func loadEffectiveConfig(gitrConfigYaml, wd string) (*EffectiveConfig, error) {
+	data, base, err := readConfigFile(gitrConfigYaml, nil)
+	if err != nil {
+		return nil, err
+	}
+	e := &EffectiveConfig{Files: []string{gitrConfigYaml}, origins: make(map[string]string), root: &yaml.Node{}}
+	if err := yaml.Unmarshal(data, e.root); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s file", gitrConfigYaml)
+	}
+	e.root = e.root.Content[0]
+	setOrigins(e.root, "", fileOrigin(gitrConfigYaml), e.origins)
+
+	if workspaceYaml := findWorkspaceConfigFile(wd, gitrConfigYaml, base); workspaceYaml != "" {
+		data, _, err := readConfigFile(workspaceYaml, base)
+		if err != nil {
+			return nil, err
+		}
+		var layer yaml.Node
+		if err := yaml.Unmarshal(data, &layer); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s file", workspaceYaml)
+		}
+		if len(layer.Content) > 0 {
+			mergeNodes(e.root, layer.Content[0], "", fileOrigin(workspaceYaml), e.origins)
+		}
+		e.Files = append(e.Files, workspaceYaml)
+	}
+
+	for _, k := range envKeys {
+		v, ok := os.LookupEnv(k.env)
+		if !ok {
+			continue
+		}
+		env := k.env
+		layer := envNode(k.key, v)
+		if err := validateEnvNode(env, layer, base); err != nil {
+			return nil, err
+		}
+		mergeNodes(e.root, layer, "", func(*yaml.Node) string { return "env " + env }, e.origins)
+		e.Env = append(e.Env, k.env)
+	}
+
+	var cfg GitrConfig
+	if err := e.root.Decode(&cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the effective config")
+	}
+	e.Config = &cfg
+	return e, nil
+}
+
+// readConfigFile reads and validates a config file, the file is merged over the base config when there is one
+func readConfigFile(path string, base *GitrConfig) ([]byte, *GitrConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read %s file", path)
+	}
+	if problems := validate(data, base); len(problems) > 0 {
+		return nil, nil, &InvalidConfigErr{Path: path, Problems: problems}
+	}
+	var cfg GitrConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to unmarshal %s file", path)
+	}
+	return data, &cfg, nil
+}
+
+// findWorkspaceConfigFile returns the nearest workspace config file of the dir or of its parents, up to the
+// outermost home dir of the base config containing the dir. Dirs inside a git repo are skipped so that files
+// committed to cloned repos are never read, and so is the config file itself when it is on the way up.
+func findWorkspaceConfigFile(dir, gitrConfigYaml string, base *GitrConfig) string {
+	root := workspaceRoot(dir, base)
+	if root == "" {
+		return ""
+	}
+	// dirs from the dir up to the root
+	dirs := []string{dir}
+	for d := dir; d != root; {
+		d = filepath.Dir(d)
+		dirs = append(dirs, d)
+	}
+	// only the dirs above the outermost git repo are workspace dirs
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if _, err := os.Stat(filepath.Join(dirs[i], ".git")); err == nil {
+			dirs = dirs[i+1:]
+			break
+		}
+	}
+	configInfo, _ := os.Stat(gitrConfigYaml)
+	for _, d := range dirs {
+		p := filepath.Join(d, WorkspaceConfigFile)
+		if info, err := os.Stat(p); err == nil && !info.IsDir() && (configInfo == nil || !os.SameFile(info, configInfo)) {
+			return p
+		}
+	}
+	return ""
+}
+
+// workspaceRoot returns the outermost of scm.homeDir and the clone home dirs of the hosts of the config containing
+// the dir, and an empty string when none of them does
+func workspaceRoot(dir string, cfg *GitrConfig) string {
+	if cfg == nil || cfg.Scm == nil {
+		return ""
+	}
+	homeDirs := []string{cfg.Scm.HomeDir}
+	for _, s := range cfg.Scm.Hosts {
+		if s != nil && s.Clone != nil {
+			homeDirs = append(homeDirs, s.Clone.HomeDir)
+		}
+	}
+	root := ""
+	for _, homeDir := range homeDirs {
+		if homeDir == "" || !filepath.IsAbs(homeDir) {
+			continue
+		}
+		homeDir = filepath.Clean(homeDir)
+		rel, err := filepath.Rel(homeDir, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if root == "" || len(homeDir) < len(root) {
+			root = homeDir
+		}
+	}
+	return root
+}
+
+// validateEnvNode validates the value of an env var like a config file with the value at the key of the env var
+func validateEnvNode(env string, n *yaml.Node, base *GitrConfig) error {
+	data, err := yaml.Marshal(n)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", env)
+	}
+	if problems := validate(data, base); len(problems) > 0 {
+		return &InvalidConfigErr{Path: "env " + env, Problems: problems}
+	}
+	return nil
+}
+
+func fileOrigin(path string) func(n *yaml.Node) string {
+	return func(n *yaml.Node) string {
+		return fmt.Sprintf("%s:%d", path, n.Line)
+	}
+}
+
+// envNode returns a mapping node with the value at the dot separated key
+func envNode(key, value string) *yaml.Node {
+	n := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	keys := strings.Split(key, ".")
+	for i := len(keys) - 1; i >= 0; i-- {
+		n = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: keys[i]}, n}}
+	}
+	return n
+}
+
+// mergeNodes merges the src node over the dst node. Mappings are merged key by key, the lists of mergeKeys item by item
+// and any other value of src replaces the one of dst.
+func mergeNodes(dst, src *yaml.Node, key string, origin func(n *yaml.Node) string, origins map[string]string) {
+	if dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			k := joinKey(key, src.Content[i].Value)
+			if _, existing := mappingValue(dst, src.Content[i].Value); existing != nil {
+				mergeNodes(existing, src.Content[i+1], k, origin, origins)
+				continue
+			}
+			dst.Content = append(dst.Content, src.Content[i], src.Content[i+1])
+			setOrigins(src.Content[i+1], k, origin, origins)
+		}
+		return
+	}
+	if itemKey, ok := mergeKeys[key]; ok && dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode {
+		for _, item := range src.Content {
+			_, id := mappingValue(item, itemKey)
+			if existing := findItem(dst, itemKey, id); existing != nil {
+				mergeNodes(existing, item, listItemKey(key, id, len(dst.Content)), origin, origins)
+				continue
+			}
+			dst.Content = append(dst.Content, item)
+			setOrigins(item, listItemKey(key, id, len(dst.Content)-1), origin, origins)
+		}
+		return
+	}
+	for k := range origins {
+		if k == key || strings.HasPrefix(k, key+".") || strings.HasPrefix(k, key+"[") {
+			delete(origins, k)
+		}
+	}
+	*dst = *src
+	setOrigins(dst, key, origin, origins)
+}
+
+// setOrigins records the origin of every value of the node
+func setOrigins(n *yaml.Node, key string, origin func(n *yaml.Node) string, origins map[string]string) {
+	walkValues(n, key, func(k string, v *yaml.Node) {
+		origins[k] = origin(v)
+	})
+}
+
+// walkValues calls fn with the key of every value of the node, mappings and the lists of mergeKeys are walked into
+func walkValues(n *yaml.Node, key string, fn func(key string, v *yaml.Node)) {
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			walkValues(n.Content[i+1], joinKey(key, n.Content[i].Value), fn)
+		}
+		return
+	}
+	if itemKey, ok := mergeKeys[key]; ok && n.Kind == yaml.SequenceNode {
+		for i, item := range n.Content {
+			_, id := mappingValue(item, itemKey)
+			walkValues(item, listItemKey(key, id, i), fn)
+		}
+		return
+	}
+	fn(key, n)
+}
+
+// findItem returns the item of the list with the id, items without an id are never found
+func findItem(list *yaml.Node, itemKey string, id *yaml.Node) *yaml.Node {
+	if id == nil {
+		return nil
+	}
+	for _, item := range list.Content {
+		if _, itemId := mappingValue(item, itemKey); itemId != nil && itemId.Value == id.Value {
+			return item
+		}
+	}
+	return nil
+}
+
+func joinKey(key, child string) string {
+	if key == "" {
+		return child
+	}
+	return key + "." + child
+}
+
+// listItemKey returns the key of an item of a list, ex: scm.hosts[github.com], using the index for items without an id
+func listItemKey(key string, id *yaml.Node, index int) string {
+	if id == nil {
+		return fmt.Sprintf("%s[%d]", key, index)
+	}
+	return fmt.Sprintf("%s[%s]", key, id.Value)
+}
+
+// Entries returns the values of the effective config in the order of the config file, with where they came from
+func (e *EffectiveConfig) Entries() []*Entry {
+	entries := make([]*Entry, 0)
+	walkValues(e.root, "", func(k string, v *yaml.Node) {
+		value := v.Value
+		if v.Kind != yaml.ScalarNode {
+			flow := *v
+			flow.Style = yaml.FlowStyle
+			d, _ := yaml.Marshal(&flow)
+			value = strings.TrimSpace(string(d))
+		}
+		entries = append(entries, &Entry{Key: k, Value: value, Origin: e.origins[k]})
+	})
+	return entries
+}
@@ -0,0 +1,216 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+const globalConfig = `defaultHost: github.com
+scm:
+  homeDir: {{homeDir}}
+  hosts:
+    - hostname: github.com
+      provider: github
+      scheme: https
+      clone:
+        alwaysCreDir: true
+        includeHostForCreDir: true
+        sparsePaths: [docs]
+    - hostname: gitlab.corp.net
+      provider: gitlab
+      clone: {}
+`
+
+const workspaceConfig = `aliases:
+  work: gitlab.corp.net
+scm:
+  hosts:
+    - hostname: github.com
+      clone:
+        alwaysCreDir: false
+        sparsePaths: [cmd, pkg]
+    - hostname: gitlab.corp.net
+      clone:
+        depth: 1
+`
+
+// setUpLayers writes the config file, with a home dir at root/scm, and the workspace config file at the root of the
+// home dir and returns the path of the config file and a dir inside the home dir
+func setUpLayers(t *testing.T, workspace string) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	gitrConfigFile := filepath.Join(root, "gitr.yaml")
+	homeDir := filepath.Join(root, "scm")
+	wd := filepath.Join(homeDir, "team", "svc")
+	if err := os.MkdirAll(wd, os.ModePerm); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(gitrConfigFile, []byte(strings.Replace(globalConfig, "{{homeDir}}", homeDir, 1)), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if workspace != "" {
+		if err := os.WriteFile(filepath.Join(homeDir, config.WorkspaceConfigFile), []byte(workspace), 0644); err != nil {
+			t.Fatalf("failed to write workspace config: %v", err)
+		}
+	}
+	t.Setenv(config.EnvConfigPath, gitrConfigFile)
+	t.Setenv("GITR_DEFAULT_HOST", "")
+	os.Unsetenv("GITR_DEFAULT_HOST")
+	t.Chdir(wd)
+	return gitrConfigFile, wd
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	t.Run("workspace config should be merged over the config file", func(t *testing.T) {
+		setUpLayers(t, workspaceConfig)
+		e, err := config.NewEffectiveConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(e.Files) != 2 {
+			t.Fatalf("expecting 2 files but got %v", e.Files)
+		}
+		cfg := e.Config
+		if len(cfg.Scm.Hosts) != 2 {
+			t.Fatalf("expecting the hosts of the config file but got %d", len(cfg.Scm.Hosts))
+		}
+		if gl, _ := config.GetScmHost(cfg, "gitlab.corp.net"); gl.Provider != config.GitLab || gl.Clone.Depth != 1 {
+			t.Errorf("expecting gitlab.corp.net to be changed by the workspace config but got %+v, %+v", gl, gl.Clone)
+		}
+		gh, _ := config.GetScmHost(cfg, "github.com")
+		if gh.Provider != config.GitHub || gh.Clone.AlwaysCreDir || !gh.Clone.IncludeHostForCreDir {
+			t.Errorf("expecting github.com to be merged key by key but got %+v, %+v", gh, gh.Clone)
+		}
+		if len(gh.Clone.SparsePaths) != 2 || gh.Clone.SparsePaths[0] != "cmd" {
+			t.Errorf("expecting lists to be replaced but got %v", gh.Clone.SparsePaths)
+		}
+		if hostname, _ := config.GetAliasHostname(cfg, "work"); hostname != "gitlab.corp.net" {
+			t.Errorf("expecting the alias of the workspace config but got %s", hostname)
+		}
+	})
+	t.Run("env vars should take precedence over the config files", func(t *testing.T) {
+		setUpLayers(t, workspaceConfig)
+		t.Setenv("GITR_DEFAULT_HOST", "gitlab.corp.net")
+		cfg, err := config.NewGitrConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.DefaultHost != "gitlab.corp.net" {
+			t.Errorf("expecting the default host of the env var but got %s", cfg.DefaultHost)
+		}
+	})
+	t.Run("origins should point at the file and line or the env var of each value", func(t *testing.T) {
+		gitrConfigFile, wd := setUpLayers(t, workspaceConfig)
+		t.Setenv("GITR_DEFAULT_HOST", "gitlab.corp.net")
+		workspaceFile := filepath.Join(filepath.Dir(filepath.Dir(wd)), config.WorkspaceConfigFile)
+		e, err := config.NewEffectiveConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := map[string]string{
+			"defaultHost":                              "env GITR_DEFAULT_HOST",
+			"scm.hosts[github.com].provider":           gitrConfigFile + ":6",
+			"scm.hosts[github.com].clone.alwaysCreDir": workspaceFile + ":7",
+			"scm.hosts[github.com].clone.sparsePaths":  workspaceFile + ":8",
+			"scm.hosts[gitlab.corp.net].provider":      gitrConfigFile + ":13",
+			"scm.hosts[gitlab.corp.net].clone.depth":   workspaceFile + ":11",
+			"aliases.work":                             workspaceFile + ":2",
+		}
+		found := 0
+		for _, entry := range e.Entries() {
+			if origin, ok := expected[entry.Key]; ok {
+				found++
+				if entry.Origin != origin {
+					t.Errorf("expecting %s to come from %s but got %s", entry.Key, origin, entry.Origin)
+				}
+			}
+		}
+		if found != len(expected) {
+			t.Errorf("expecting %d entries but found %d", len(expected), found)
+		}
+	})
+	t.Run("workspace configs with problems should fail with their path", func(t *testing.T) {
+		setUpLayers(t, "scm:\n  hosts:\n    - hostname: gitea.corp.net\n      clone: {}\n")
+		_, err := config.NewGitrConfig()
+		invalid, ok := err.(*config.InvalidConfigErr)
+		if !ok || filepath.Base(invalid.Path) != config.WorkspaceConfigFile {
+			t.Errorf("expecting an invalid workspace config error but got %v", err)
+		}
+	})
+	t.Run("workspace configs should not set where the credentials of a host are sent", func(t *testing.T) {
+		setUpLayers(t, "scm:\n  hosts:\n    - hostname: github.com\n      apiUrl: https://evil.example.com\n      authOrder: [env]\n")
+		_, err := config.NewGitrConfig()
+		invalid, ok := err.(*config.InvalidConfigErr)
+		if !ok || len(invalid.Problems) != 2 || !strings.Contains(invalid.Problems[0].Message, "apiUrl") {
+			t.Errorf("expecting the credential keys to be reported but got %v", err)
+		}
+	})
+	t.Run("workspace configs should not define providers or change the provider of a host", func(t *testing.T) {
+		setUpLayers(t, "providers:\n  - name: forge\n    repoPathRegex: ^/(.+)\nscm:\n  hosts:\n    - hostname: github.com\n      provider: forge\n")
+		_, err := config.NewGitrConfig()
+		invalid, ok := err.(*config.InvalidConfigErr)
+		if !ok || len(invalid.Problems) != 2 || !strings.Contains(invalid.Problems[0].Message, "providers") ||
+			!strings.HasPrefix(invalid.Problems[1].Message, "provider ") {
+			t.Errorf("expecting the providers and the provider of the host to be reported but got %v", err)
+		}
+	})
+	t.Run("invalid env values should fail with the env var", func(t *testing.T) {
+		setUpLayers(t, "")
+		t.Setenv("GITR_COPY_REPO_PATH_CD_CMD_TO_CLIPBOARD", "sometimes")
+		_, err := config.NewGitrConfig()
+		invalid, ok := err.(*config.InvalidConfigErr)
+		if !ok || invalid.Path != "env GITR_COPY_REPO_PATH_CD_CMD_TO_CLIPBOARD" {
+			t.Errorf("expecting an invalid env var error but got %v", err)
+		}
+	})
+	t.Run("workspace configs inside git repos should not be read", func(t *testing.T) {
+		_, wd := setUpLayers(t, workspaceConfig)
+		if err := os.Mkdir(filepath.Join(wd, ".git"), os.ModePerm); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(wd, config.WorkspaceConfigFile), []byte("defaultHost: evil.example.com\n"), 0644); err != nil {
+			t.Fatalf("failed to write workspace config: %v", err)
+		}
+		e, err := config.NewEffectiveConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(e.Files) != 2 || e.Config.DefaultHost != "github.com" {
+			t.Errorf("expecting only the workspace config of the home dir but got %v", e.Files)
+		}
+	})
+	t.Run("workspace configs outside of the home dir should not be read", func(t *testing.T) {
+		_, wd := setUpLayers(t, "")
+		outside := filepath.Dir(filepath.Dir(filepath.Dir(wd)))
+		if err := os.WriteFile(filepath.Join(outside, config.WorkspaceConfigFile), []byte("defaultHost: evil.example.com\n"), 0644); err != nil {
+			t.Fatalf("failed to write workspace config: %v", err)
+		}
+		e, err := config.NewEffectiveConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(e.Files) != 1 {
+			t.Errorf("expecting the config file alone but got %v", e.Files)
+		}
+	})
+	t.Run("config file should not be merged twice when it is on the way up", func(t *testing.T) {
+		_, wd := setUpLayers(t, "")
+		homeDir := filepath.Dir(filepath.Dir(wd))
+		gitrConfigFile := filepath.Join(homeDir, config.WorkspaceConfigFile)
+		if err := os.WriteFile(gitrConfigFile, []byte(strings.Replace(globalConfig, "{{homeDir}}", homeDir, 1)), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		t.Setenv(config.EnvConfigPath, gitrConfigFile)
+		e, err := config.NewEffectiveConfig()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(e.Files) != 1 {
+			t.Errorf("expecting the config file alone but got %v", e.Files)
+		}
+	})
+}
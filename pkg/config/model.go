@@ -3,6 +3,12 @@ package config
 type GitrConfig struct {
 	CopyRepoPathCdCmdToClipboard bool `yaml:"copyRepoPathCdCmdToClipboard"`
 	Scm                          *Scm `yaml:"scm"`
+	// DefaultHost is the hostname used for shorthand repo references without a host, ex: owner/repo
+	DefaultHost string `yaml:"defaultHost,omitempty"`
+	// Aliases maps short names to hostnames for shorthand repo references, ex: work:team/svc
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+	// Providers are providers defined in the config file, for forges gitr does not support natively
+	Providers []*CustomProvider `yaml:"providers,omitempty"`
 }
 
 type Scm struct {
@@ -16,10 +22,56 @@ type ScmHost struct {
 	DefaultBranch string       `yaml:"defaultBranch"`
 	Clone         *CloneConfig `yaml:"clone"`
 	Scheme        HttpScheme   `yaml:"scheme"`
+	ApiUrl        string       `yaml:"apiUrl,omitempty"`
+	AuthOrder     []string     `yaml:"authOrder,omitempty"`
+	SshUser       string       `yaml:"sshUser,omitempty"`
+	SshPort       int          `yaml:"sshPort,omitempty"`
+	// SshCloneUrlTemplate and HttpCloneUrlTemplate are text/template strings used to build clone urls
+	// from browser urls, ex: ssh://{{.SshUser}}@{{.Hostname}}:{{.SshPort}}/{{.RepoPath}}.git
+	SshCloneUrlTemplate  string `yaml:"sshCloneUrlTemplate,omitempty"`
+	HttpCloneUrlTemplate string `yaml:"httpCloneUrlTemplate,omitempty"`
+	// Browser and BrowserUrl select the repo browser of gerrit hosts, gitiles under <host>/plugins/gitiles when not set
+	Browser    GerritBrowser `yaml:"browser,omitempty"`
+	BrowserUrl string        `yaml:"browserUrl,omitempty"`
 }
 
 type CloneConfig struct {
-	HomeDir              string `yaml:"homeDir"`
-	AlwaysCreDir         bool   `yaml:"alwaysCreDir"`
-	IncludeHostForCreDir bool   `yaml:"includeHostForCreDir"`
+	HomeDir              string            `yaml:"homeDir"`
+	AlwaysCreDir         bool              `yaml:"alwaysCreDir"`
+	IncludeHostForCreDir bool              `yaml:"includeHostForCreDir"`
+	Depth                int               `yaml:"depth,omitempty"`
+	Filter               string            `yaml:"filter,omitempty"`
+	SingleBranch         bool              `yaml:"singleBranch,omitempty"`
+	SparsePaths          []string          `yaml:"sparsePaths,omitempty"`
+	OnExistingDir        ExistingDirPolicy `yaml:"onExistingDir,omitempty"`
+	Backend              CloneBackend      `yaml:"backend,omitempty"`
+	Submodules           SubmoduleMode     `yaml:"submodules,omitempty"`
+	Lfs                  LfsMode           `yaml:"lfs,omitempty"`
+}
+
+// CustomProvider is a provider of scm hosts defined in the config file. Hosts use it by its name.
+type CustomProvider struct {
+	Name ScmProvider `yaml:"name"`
+	// Base is a built-in provider used for the urls the custom provider does not define, pages are not supported without one
+	Base ScmProvider `yaml:"base,omitempty"`
+	// RepoPathRegex is matched against the part of browser urls following the hostname, the repo path is its first group,
+	// ex: ^/(?:projects/)?([^/]+/[^/]+)
+	RepoPathRegex string              `yaml:"repoPathRegex,omitempty"`
+	Urls          *CustomProviderUrls `yaml:"urls,omitempty"`
+}
+
+// CustomProviderUrls are text/template strings, with the sprig functions, for the urls of a custom provider.
+// Web is executed with .Scheme, .Hostname and .RepoPath, pages also get .WebUrl, .Branch, .Ref and .Path,
+// ex: {{.WebUrl}}/merge-requests?state=open
+type CustomProviderUrls struct {
+	Web       string `yaml:"web,omitempty"`
+	Branch    string `yaml:"branch,omitempty"`
+	Prs       string `yaml:"prs,omitempty"`
+	Pipelines string `yaml:"pipelines,omitempty"`
+	Issues    string `yaml:"issues,omitempty"`
+	Branches  string `yaml:"branches,omitempty"`
+	Commits   string `yaml:"commits,omitempty"`
+	Tags      string `yaml:"tags,omitempty"`
+	Releases  string `yaml:"releases,omitempty"`
+	File      string `yaml:"file,omitempty"`
 }
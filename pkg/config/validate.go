@@ -0,0 +1,297 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScmProviders are the providers supported natively by gitr, hosts can also use the providers of the config file
+var ScmProviders = []ScmProvider{GitHub, GitLab, BitBucketCloud, BitBucketDatacenter, Gitea, AzureDevOps, Gerrit, SourceHut}
+
+// Problem is a mistake found in a config file, on the line of the yaml it was found on
+type Problem struct {
+	Line    int
+	Message string
+}
+
+func (p *Problem) String() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// InvalidConfigErr is returned for config files with problems
+type InvalidConfigErr struct {
+	Path     string
+	Problems []*Problem
+}
+
+func (e *InvalidConfigErr) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("%s has %d problem(s):", e.Path, len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, "  "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// yamlErrLine matches the line of the errors of the yaml decoder, ex: line 4: field hostnme not found in type config.ScmHost
+var yamlErrLine = regexp.MustCompile(`line (\d+): (.*)`)
+
+// yamlUnknownField matches the errors of the yaml decoder for unknown keys, ex: field hostnme not found in type config.ScmHost
+var yamlUnknownField = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+
+// Validate returns the problems of the contents of a config file: yaml errors, unknown keys, duplicate hosts,
+// unknown providers, schemes and other enum values and hosts without a clone block
+func Validate(data []byte) []*Problem {
+	return validate(data, nil)
+}
+
+// validate returns the problems of the contents of a config file merged over the base config. The scm block is
+// optional in files with a base, which can only change the settings of the hosts of the base config
+// other than the credentialKeys, and can not define providers.
+func validate(data []byte, base *GitrConfig) []*Problem {
+	problems := make([]*Problem, 0)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var cfg GitrConfig
+	if err := dec.Decode(&cfg); err != nil {
+		if err == io.EOF {
+			return append(problems, &Problem{Line: 1, Message: "config file is empty"})
+		}
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			// syntax errors stop the decoder, nothing else can be checked
+			return append(problems, yamlProblem(err.Error()))
+		}
+		for _, e := range typeErr.Errors {
+			problems = append(problems, yamlProblem(e))
+		}
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return problems
+	}
+	doc := root.Content[0]
+	scmKey, scmNode := mappingValue(doc, "scm")
+	if scmNode == nil || cfg.Scm == nil {
+		if base != nil {
+			return problems
+		}
+		return append(problems, &Problem{Line: lineOf(scmKey, doc), Message: "scm block is missing"})
+	}
+	if providersKey, _ := mappingValue(doc, "providers"); base != nil && providersKey != nil {
+		problems = append(problems, &Problem{Line: providersKey.Line, Message: "providers can only be defined in the config file"})
+	}
+	_, hostsNode := mappingValue(scmNode, "hosts")
+	if hostsNode == nil || hostsNode.Kind != yaml.SequenceNode || len(hostsNode.Content) != len(cfg.Scm.Hosts) {
+		return problems
+	}
+	baseHosts := make(map[string]bool)
+	if base != nil && base.Scm != nil {
+		for _, s := range base.Scm.Hosts {
+			baseHosts[s.Hostname] = true
+		}
+	}
+	providers := make(map[ScmProvider]bool)
+	names := make([]string, 0, len(ScmProviders)+len(cfg.Providers))
+	for _, p := range ScmProviders {
+		providers[p] = true
+		names = append(names, string(p))
+	}
+	for _, p := range cfg.Providers {
+		if !providers[p.Name] {
+			providers[p.Name] = true
+			names = append(names, string(p.Name))
+		}
+	}
+	hostLines := make(map[string]int)
+	for i, s := range cfg.Scm.Hosts {
+		hostNode := hostsNode.Content[i]
+		if s == nil {
+			problems = append(problems, &Problem{Line: hostNode.Line, Message: "host is empty"})
+			continue
+		}
+		hostnameKey, hostnameNode := mappingValue(hostNode, "hostname")
+		line := lineOf(hostnameNode, hostnameKey, hostNode)
+		if s.Hostname == "" {
+			problems = append(problems, &Problem{Line: line, Message: "host has no hostname"})
+		} else if firstLine, ok := hostLines[s.Hostname]; ok {
+			problems = append(problems, &Problem{Line: line, Message: fmt.Sprintf("duplicate host %s, already defined on line %d", s.Hostname, firstLine)})
+		} else {
+			hostLines[s.Hostname] = line
+		}
+		if base != nil {
+			problems = append(problems, credentialKeyProblems(hostNode)...)
+			if !baseHosts[s.Hostname] && s.Hostname != "" {
+				problems = append(problems, &Problem{
+					Line:    line,
+					Message: fmt.Sprintf("host %s is not in the config file, workspace config files can only change its hosts", s.Hostname),
+				})
+				continue
+			}
+		}
+		providerKey, providerNode := mappingValue(hostNode, "provider")
+		if base == nil && !providers[s.Provider] {
+			problems = append(problems, &Problem{
+				Line:    lineOf(providerNode, providerKey, hostNode),
+				Message: fmt.Sprintf("unknown provider %q for host %s, expecting one of %s", s.Provider, s.Hostname, strings.Join(names, ", ")),
+			})
+		}
+		schemeKey, schemeNode := mappingValue(hostNode, "scheme")
+		if s.Scheme != "" && s.Scheme != Http && s.Scheme != Https {
+			problems = append(problems, &Problem{
+				Line:    lineOf(schemeNode, schemeKey),
+				Message: fmt.Sprintf("unknown scheme %q for host %s, expecting %s or %s", s.Scheme, s.Hostname, Http, Https),
+			})
+		}
+		problems = appendProblem(problems, enumProblem(hostNode, "browser", s.Browser, s.Hostname, GerritBrowserGitiles, GerritBrowserGitweb))
+		cloneKey, cloneNode := mappingValue(hostNode, "clone")
+		if s.Clone != nil {
+			problems = appendProblem(problems, enumProblem(cloneNode, "onExistingDir", s.Clone.OnExistingDir, s.Hostname,
+				ExistingDirFail, ExistingDirBackup, ExistingDirPrompt, ExistingDirOverwrite))
+			problems = appendProblem(problems, enumProblem(cloneNode, "backend", s.Clone.Backend, s.Hostname,
+				CloneBackendAuto, CloneBackendExec, CloneBackendGoGit))
+			problems = appendProblem(problems, enumProblem(cloneNode, "submodules", s.Clone.Submodules, s.Hostname,
+				SubmodulesNone, SubmodulesRecurse, SubmodulesManaged))
+			problems = appendProblem(problems, enumProblem(cloneNode, "lfs", s.Clone.Lfs, s.Hostname, LfsPull, LfsSkip))
+		}
+		// hosts of the base config are only changed by the file
+		if s.Clone == nil && base == nil {
+			problems = append(problems, &Problem{
+				Line:    lineOf(cloneKey, hostNode),
+				Message: fmt.Sprintf("host %s has no clone block, add one with at least alwaysCreDir and includeHostForCreDir", s.Hostname),
+			})
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+	return problems
+}
+
+// enumProblem returns a problem when the value of the key of a mapping is set and is not one of the allowed values
+func enumProblem[T ~string](n *yaml.Node, key string, value T, hostname string, allowed ...T) *Problem {
+	if value == "" {
+		return nil
+	}
+	names := make([]string, 0, len(allowed))
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+		names = append(names, string(a))
+	}
+	keyNode, valueNode := mappingValue(n, key)
+	return &Problem{
+		Line:    lineOf(valueNode, keyNode, n),
+		Message: fmt.Sprintf("unknown %s %q for host %s, expecting one of %s", key, value, hostname, strings.Join(names, ", ")),
+	}
+}
+
+func appendProblem(problems []*Problem, p *Problem) []*Problem {
+	if p == nil {
+		return problems
+	}
+	return append(problems, p)
+}
+
+// credentialKeys are the settings of a host that decide where its credentials are sent. They can only be set in
+// the config file, never in a workspace config file that could come with a cloned repo.
+var credentialKeys = map[string]bool{
+	"provider":             true,
+	"apiUrl":               true,
+	"authOrder":            true,
+	"scheme":               true,
+	"sshCloneUrlTemplate":  true,
+	"httpCloneUrlTemplate": true,
+}
+
+func credentialKeyProblems(hostNode *yaml.Node) []*Problem {
+	problems := make([]*Problem, 0)
+	if hostNode.Kind != yaml.MappingNode {
+		return problems
+	}
+	for i := 0; i+1 < len(hostNode.Content); i += 2 {
+		if key := hostNode.Content[i]; credentialKeys[key.Value] {
+			problems = append(problems, &Problem{
+				Line:    key.Line,
+				Message: fmt.Sprintf("%s decides where the credentials of the host are sent, it can only be set in the config file", key.Value),
+			})
+		}
+	}
+	return problems
+}
+
+// yamlProblem returns the problem of an error of the yaml decoder
+func yamlProblem(msg string) *Problem {
+	msg = strings.TrimPrefix(msg, "yaml: ")
+	m := yamlErrLine.FindStringSubmatch(msg)
+	if m == nil {
+		return &Problem{Line: 1, Message: msg}
+	}
+	line, _ := strconv.Atoi(m[1])
+	if f := yamlUnknownField.FindStringSubmatch(m[2]); f != nil {
+		return &Problem{Line: line, Message: fmt.Sprintf("unknown key %s", f[1])}
+	}
+	return &Problem{Line: line, Message: m[2]}
+}
+
+// Warnings returns the problems of the contents of a config file that do not stop gitr from loading it:
+// home dirs that do not exist yet, as the first clone creates them
+func Warnings(data []byte) []*Problem {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+	_, scmNode := mappingValue(root.Content[0], "scm")
+	problems := homeDirProblems(mappingValue(scmNode, "homeDir"))
+	_, hostsNode := mappingValue(scmNode, "hosts")
+	if hostsNode == nil || hostsNode.Kind != yaml.SequenceNode {
+		return problems
+	}
+	for _, hostNode := range hostsNode.Content {
+		_, cloneNode := mappingValue(hostNode, "clone")
+		problems = append(problems, homeDirProblems(mappingValue(cloneNode, "homeDir"))...)
+	}
+	return problems
+}
+
+func homeDirProblems(keyNode, valueNode *yaml.Node) []*Problem {
+	if valueNode == nil || valueNode.Value == "" {
+		return nil
+	}
+	if info, err := os.Stat(valueNode.Value); err != nil || !info.IsDir() {
+		return []*Problem{{Line: lineOf(valueNode, keyNode), Message: fmt.Sprintf("homeDir %s does not exist", valueNode.Value)}}
+	}
+	return nil
+}
+
+// mappingValue returns the key and the value nodes of the key of a mapping node, nil when the key is not set
+func mappingValue(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i], n.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// lineOf returns the line of the first of the nodes that is set
+func lineOf(nodes ...*yaml.Node) int {
+	for _, n := range nodes {
+		if n != nil {
+			return n.Line
+		}
+	}
+	return 1
+}
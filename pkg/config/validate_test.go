@@ -0,0 +1,150 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+func TestValidate(t *testing.T) {
+	homeDir := t.TempDir()
+	var tests = []struct {
+		name     string
+		yaml     string
+		expected []string
+	}{
+		{"valid config should have no problems", `
+scm:
+  homeDir: ` + homeDir + `
+  hosts:
+    - hostname: github.com
+      provider: github
+      scheme: https
+      clone:
+        alwaysCreDir: true
+    - hostname: forge.example.com
+      provider: forge
+      clone: {}
+providers:
+  - name: forge
+    repoPathRegex: ^/(.+)
+`, nil},
+		{"unknown keys should be reported", `
+scm:
+  hosts:
+    - hostname: github.com
+      provider: github
+      clone:
+        alwaysCreDir: true
+        depht: 1
+`, []string{"line 8: unknown key depht"}},
+		{"duplicate hosts, unknown providers and schemes and missing clone blocks should be reported", `
+scm:
+  hosts:
+    - hostname: github.com
+      provider: github
+      clone: {}
+    - hostname: github.com
+      provider: githb
+      scheme: ftp
+`, []string{
+			"line 7: duplicate host github.com, already defined on line 4",
+			"line 7: host github.com has no clone block",
+			`line 8: unknown provider "githb"`,
+			`line 9: unknown scheme "ftp"`,
+		}},
+		{"unknown clone settings and browsers should be reported", `
+scm:
+  hosts:
+    - hostname: review.example.com
+      provider: gerrit
+      browser: cgit
+      clone:
+        onExistingDir: keep
+        backend: libgit2
+        submodules: all
+        lfs: fetch
+`, []string{
+			`line 6: unknown browser "cgit" for host review.example.com, expecting one of gitiles, gitweb`,
+			`line 8: unknown onExistingDir "keep"`,
+			`line 9: unknown backend "libgit2"`,
+			`line 10: unknown submodules "all"`,
+			`line 11: unknown lfs "fetch"`,
+		}},
+		{"home dirs that do not exist should not be problems", `
+scm:
+  homeDir: /does/not/exist
+  hosts:
+    - hostname: github.com
+      provider: github
+      clone:
+        homeDir: /does/not/exist/either
+`, nil},
+		{"syntax errors should be reported", "scm:\n  hosts: [\n", []string{"line 2:"}},
+		{"empty files should be reported", "", []string{"line 1: config file is empty"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := config.Validate([]byte(tc.yaml))
+			if len(problems) != len(tc.expected) {
+				t.Fatalf("expecting %d problems but got %d: %v", len(tc.expected), len(problems), problems)
+			}
+			for i, p := range problems {
+				if !strings.HasPrefix(p.String(), tc.expected[i]) {
+					t.Errorf("expecting a problem starting with %s but got %s", tc.expected[i], p)
+				}
+			}
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	homeDir := t.TempDir()
+	yaml := `
+scm:
+  homeDir: /does/not/exist
+  hosts:
+    - hostname: github.com
+      provider: github
+      clone:
+        homeDir: /does/not/exist/either
+    - hostname: gitlab.com
+      provider: gitlab
+      clone:
+        homeDir: ` + homeDir + `
+`
+	t.Run("home dirs that do not exist should be warned about", func(t *testing.T) {
+		expected := []string{"line 3: homeDir /does/not/exist does not exist", "line 8: homeDir /does/not/exist/either does not exist"}
+		warnings := config.Warnings([]byte(yaml))
+		if len(warnings) != len(expected) {
+			t.Fatalf("expecting %d warnings but got %d: %v", len(expected), len(warnings), warnings)
+		}
+		for i, w := range warnings {
+			if w.String() != expected[i] {
+				t.Errorf("expecting %s but got %s", expected[i], w)
+			}
+		}
+	})
+}
+
+func TestNewGitrConfigValidation(t *testing.T) {
+	gitrConfigFile := filepath.Join(t.TempDir(), "gitr.yaml")
+	t.Setenv(config.EnvConfigPath, gitrConfigFile)
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile(gitrConfigFile, []byte("scm:\n  hosts:\n    - hostname: github.com\n      provider: github\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Run("loading a config with problems should fail", func(t *testing.T) {
+		_, err := config.NewGitrConfig()
+		invalid, ok := err.(*config.InvalidConfigErr)
+		if !ok {
+			t.Fatalf("expecting an invalid config error but got %v", err)
+		}
+		if invalid.Path != gitrConfigFile || len(invalid.Problems) != 1 || invalid.Problems[0].Line != 3 {
+			t.Errorf("unexpected problems %v", invalid.Problems)
+		}
+	})
+}
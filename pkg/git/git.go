@@ -2,10 +2,13 @@ package git
 
 import (
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -27,20 +30,45 @@ func GetGitRepo(folder string) (*git.Repository, error) {
 	return nil, errors.New("git repository not found in the folder tree")
 }
 
-// GetGitRemoteUrl returns the first url in the first remote found in the git repository object
-// and returns an errors either if there is no remotes or if there is no urls for the first remote.
+// UpstreamRemoteName is the name of the remote pointing at the repository a fork was created from
+const UpstreamRemoteName = "upstream"
+
+// GetGitRemoteUrl returns the first url of the primary remote of the git repository object
+// and returns an errors either if there is no remotes or if there is no urls for the primary remote.
+// The primary remote is "origin" when present and otherwise the first remote by name.
 func GetGitRemoteUrl(r *git.Repository) (string, error) {
-	remotes, err := r.Remotes()
+	remoteName, err := GetRemoteName(r)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to get remotes from git repo")
+		return "", err
 	}
-	if len(remotes) == 0 {
-		return "", errors.New("no remotes found")
+	return GetGitRemoteUrlByName(r, remoteName)
+}
+
+// GetGitRemoteUrlByName returns the first url of the remote with the given name
+func GetGitRemoteUrlByName(r *git.Repository, name string) (string, error) {
+	remote, err := r.Remote(name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get %s remote", name)
 	}
-	if len(remotes[0].Config().URLs) == 0 {
-		return "", errors.Errorf("urls not found for %s remote", remotes[0].Config().Name)
+	if len(remote.Config().URLs) == 0 {
+		return "", errors.Errorf("urls not found for %s remote", name)
 	}
-	return remotes[0].Config().URLs[0], nil
+	return remote.Config().URLs[0], nil
+}
+
+// AddRemote adds a remote with the url to the repository.
+// Adding a remote that already exists with the same url is not an error.
+func AddRemote(r *git.Repository, name, remoteUrl string) error {
+	if existingUrl, err := GetGitRemoteUrlByName(r, name); err == nil {
+		if existingUrl == remoteUrl {
+			return nil
+		}
+		return errors.Errorf("%s remote already exists with url %s", name, existingUrl)
+	}
+	if _, err := r.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{remoteUrl}}); err != nil {
+		return errors.Wrapf(err, "failed to add %s remote", name)
+	}
+	return nil
 }
 
 // GetGitBranch returns the name of the current branch
@@ -56,14 +84,12 @@ func GetGitBranch(r *git.Repository) (string, error) {
 // by checking local remote-tracking branches (e.g., refs/remotes/origin/branch-name)
 // This method uses local information and doesn't require network access or authentication
 func DoesBranchExistOnRemote(r *git.Repository, branchName string) bool {
-	remotes, err := r.Remotes()
-	if err != nil || len(remotes) == 0 {
+	remoteName, err := GetRemoteName(r)
+	if err != nil {
 		log.Debugf("no remotes found")
 		return false
 	}
 
-	remoteName := remotes[0].Config().Name
-
 	// Check for remote-tracking branch (e.g., refs/remotes/origin/branch-name)
 	remoteTrackingRef := "refs/remotes/" + remoteName + "/" + branchName
 
@@ -89,13 +115,11 @@ func DoesBranchExistOnRemote(r *git.Repository, branchName string) bool {
 // by checking the local remote HEAD reference (e.g., refs/remotes/origin/HEAD)
 // This method uses local information and doesn't require network access or authentication
 func GetDefaultBranch(r *git.Repository) (string, error) {
-	remotes, err := r.Remotes()
-	if err != nil || len(remotes) == 0 {
+	remoteName, err := GetRemoteName(r)
+	if err != nil {
 		return "", errors.New("no remotes found")
 	}
 
-	remoteName := remotes[0].Config().Name
-
 	// Try to get the remote HEAD reference (e.g., refs/remotes/origin/HEAD)
 	remoteHeadRef := "refs/remotes/" + remoteName + "/HEAD"
 
@@ -128,3 +152,130 @@ func GetDefaultBranch(r *git.Repository) (string, error) {
 
 	return "", errors.New("unable to determine default branch")
 }
+
+// IsWorktreeClean returns true when the worktree of the repository has no uncommitted changes
+func IsWorktreeClean(r *git.Repository) (bool, error) {
+	wt, err := r.Worktree()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get worktree")
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get worktree status")
+	}
+	return status.IsClean(), nil
+}
+
+// Fetch fetches the refspecs from the remote with the name, no deeper than depth when it is set.
+// Fetching when everything is already up to date is not an error.
+func Fetch(r *git.Repository, remoteName string, refSpecs []string, depth int, auth transport.AuthMethod) error {
+	specs := make([]config.RefSpec, 0, len(refSpecs))
+	for _, refSpec := range refSpecs {
+		specs = append(specs, config.RefSpec(refSpec))
+	}
+	err := r.Fetch(&git.FetchOptions{RemoteName: remoteName, RefSpecs: specs, Depth: depth, Auth: auth})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return errors.Wrapf(err, "failed to fetch %s", remoteName)
+	}
+	return nil
+}
+
+// ResolvedRef is the ref of the repository named by one of the candidates of a browser url.
+// Ref is a local branch, a remote branch to create the local branch from, or a tag.
+type ResolvedRef struct {
+	Name string
+	Ref  plumbing.ReferenceName
+	// Hash is the commit the ref points at, peeled for annotated tags
+	Hash plumbing.Hash
+}
+
+// ResolveRef returns the first candidate that names a local branch, a branch of the primary remote or a tag
+func ResolveRef(r *git.Repository, candidates []string) (*ResolvedRef, error) {
+	remoteName, err := GetRemoteName(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range candidates {
+		for _, refName := range []plumbing.ReferenceName{
+			plumbing.NewBranchReferenceName(name),
+			plumbing.NewRemoteReferenceName(remoteName, name),
+		} {
+			if ref, err := r.Reference(refName, true); err == nil {
+				return &ResolvedRef{Name: name, Ref: refName, Hash: ref.Hash()}, nil
+			}
+		}
+		if tagRef, err := r.Tag(name); err == nil {
+			hash := tagRef.Hash()
+			if tagObj, err := r.TagObject(hash); err == nil {
+				hash = tagObj.Target
+			}
+			return &ResolvedRef{Name: name, Ref: tagRef.Name(), Hash: hash}, nil
+		}
+	}
+	return nil, errors.Errorf("no branch or tag named %s found", candidates[len(candidates)-1])
+}
+
+// CheckoutRef checks out the first candidate that names a branch or a tag of the repository.
+// Branches are checked out as local branches tracking the remote branch and tags in detached mode.
+// The name of the checked out ref is returned.
+func CheckoutRef(r *git.Repository, candidates []string) (string, error) {
+	resolved, err := ResolveRef(r, candidates)
+	if err != nil {
+		return "", err
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get worktree")
+	}
+	name := resolved.Name
+	switch {
+	case resolved.Ref.IsBranch():
+		log.Debugf("checking out local branch %s", name)
+		return name, wt.Checkout(&git.CheckoutOptions{Branch: resolved.Ref})
+	case resolved.Ref.IsRemote():
+		localRef := plumbing.NewBranchReferenceName(name)
+		log.Debugf("creating local branch %s from %s", name, resolved.Ref)
+		if err := wt.Checkout(&git.CheckoutOptions{Branch: localRef, Hash: resolved.Hash, Create: true}); err != nil {
+			return "", errors.Wrapf(err, "failed to checkout %s", name)
+		}
+		return name, TrackRemoteBranch(r, name)
+	default:
+		log.Debugf("checking out tag %s", name)
+		return name, wt.Checkout(&git.CheckoutOptions{Hash: resolved.Hash})
+	}
+}
+
+// TrackRemoteBranch sets the branch of the primary remote with the same name as the upstream of a local branch.
+// The branch config is written directly since the fetch refspec of single branch clones does not cover the branch.
+func TrackRemoteBranch(r *git.Repository, name string) error {
+	remoteName, err := GetRemoteName(r)
+	if err != nil {
+		return err
+	}
+	err = r.CreateBranch(&config.Branch{Name: name, Remote: remoteName, Merge: plumbing.NewBranchReferenceName(name)})
+	if err != nil && !errors.Is(err, git.ErrBranchExists) {
+		return errors.Wrapf(err, "failed to set upstream of %s", name)
+	}
+	return nil
+}
+
+// GetRemoteName returns the name of the primary remote, which is "origin" when present
+// and otherwise the first remote by name, as go-git returns remotes in no particular order
+func GetRemoteName(r *git.Repository) (string, error) {
+	remotes, err := r.Remotes()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get remotes from git repo")
+	}
+	if len(remotes) == 0 {
+		return "", errors.New("no remotes found")
+	}
+	names := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		if remote.Config().Name == git.DefaultRemoteName {
+			return git.DefaultRemoteName, nil
+		}
+		names = append(names, remote.Config().Name)
+	}
+	sort.Strings(names)
+	return names[0], nil
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+type azureDevOps struct {
+	base
+}
+
+// azure devops only advertises the merge ref of pull requests
+var azureDevOpsRefs = refPatterns{pull: "/pullrequest/", remoteRef: "refs/pull/%d/merge", localBranch: "pr-%d"}
+
+func (a *azureDevOps) ParseBrowserURL(path string) (string, *Ref, error) {
+	repoPath, err := azureDevOpsRepoPath(path)
+	if err != nil {
+		return "", nil, err
+	}
+	ref, err := azureDevOpsRefs.parse(path)
+	return repoPath, ref, err
+}
+
+func (a *azureDevOps) ParseCloneURL(path string) (string, error) {
+	return azureDevOpsRepoPath(path)
+}
+
+// azureDevOpsRepoPath returns org/project/repo from the path of an azure devops url,
+// ex: /org/project/_git/repo/pullrequest/12 for http urls and v3/org/project/repo for ssh urls
+func azureDevOpsRepoPath(path string) (string, error) {
+	path = strings.TrimSuffix(strings.TrimPrefix(strings.Trim(path, "/"), "v3/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) >= 4 && parts[2] == "_git" {
+		return strings.Join([]string{parts[0], parts[1], parts[3]}, "/"), nil
+	}
+	if len(parts) == 3 && parts[0] != "" {
+		return path, nil
+	}
+	return "", errors.Errorf("%s is not an azure devops repo url, expecting /<org>/<project>/_git/<repo>", path)
+}
+
+// azureDevOpsGitPath returns the path of the repo in azure devops http urls, ex: org/project/_git/repo for org/project/repo
+func azureDevOpsGitPath(repoPath string) string {
+	parts := strings.Split(repoPath, "/")
+	if len(parts) != 3 {
+		return repoPath
+	}
+	return strings.Join([]string{parts[0], parts[1], "_git", parts[2]}, "/")
+}
+
+// azureDevOpsProjectUrl returns the url of the project of a repo web url, pipelines and work items belong to the project
+func azureDevOpsProjectUrl(webUrl string) string {
+	if idx := strings.Index(webUrl, "/_git/"); idx != -1 {
+		return webUrl[:idx]
+	}
+	return webUrl
+}
+
+// SshCloneURL returns the ssh clone url, served from ssh.<host> under v3, ex: git@ssh.dev.azure.com:v3/org/project/repo
+func (a *azureDevOps) SshCloneURL(d *CloneURLData) string {
+	return fmt.Sprintf("%s@ssh.%s:v3/%s", d.SshUser, d.Hostname, d.RepoPath)
+}
+
+func (a *azureDevOps) HttpCloneURL(d *CloneURLData) string {
+	return fmt.Sprintf("%s://%s/%s", d.Scheme, d.Hostname, azureDevOpsGitPath(d.RepoPath))
+}
+
+func (a *azureDevOps) RepoWebURL(scheme config.HttpScheme, host, repoPath string) string {
+	return fmt.Sprintf("%s://%s/%s", scheme, host, azureDevOpsGitPath(repoPath))
+}
+
+func (a *azureDevOps) RemURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s?version=GB%s", webUrl, branch), nil
+}
+
+func (a *azureDevOps) PrsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/pullrequests", webUrl), nil
+}
+
+func (a *azureDevOps) BranchesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/branches", webUrl), nil
+}
+
+func (a *azureDevOps) CommitsURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/commits?itemVersion=GB%s", webUrl, branch), nil
+}
+
+func (a *azureDevOps) TagsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/tags", webUrl), nil
+}
+
+func (a *azureDevOps) IssuesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/_workitems", azureDevOpsProjectUrl(webUrl)), nil
+}
+
+func (a *azureDevOps) PipelinesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/_build", azureDevOpsProjectUrl(webUrl)), nil
+}
+
+func (a *azureDevOps) FileURL(webUrl, ref, rel string) (string, error) {
+	return fmt.Sprintf("%s?path=/%s&version=GB%s", webUrl, rel, ref), nil
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+type bitBucketCloud struct {
+	base
+}
+
+// bitbucket cloud does not advertise refs for pull requests
+var bitBucketCloudRefs = refPatterns{names: []string{"/src/", "/branch/"}}
+
+// ParseBrowserURL returns workspace/repo, ex: from /workspace/repo/src/main/README.md and /workspace/repo/pull-requests/12
+func (b *bitBucketCloud) ParseBrowserURL(path string) (string, *Ref, error) {
+	repoPath, err := ownerRepoPath(path, b.name)
+	if err != nil {
+		return "", nil, err
+	}
+	ref, err := bitBucketCloudRefs.parse(path)
+	return repoPath, ref, err
+}
+
+func (b *bitBucketCloud) RemURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/branch/%s", webUrl, branch), nil
+}
+
+func (b *bitBucketCloud) PrsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/pull-requests", webUrl), nil
+}
+
+func (b *bitBucketCloud) BranchesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/branches", webUrl), nil
+}
+
+func (b *bitBucketCloud) CommitsURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/commits/%s", webUrl, branch), nil
+}
+
+func (b *bitBucketCloud) TagsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/tags", webUrl), nil
+}
+
+func (b *bitBucketCloud) PipelinesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/addon/pipelines/home", webUrl), nil
+}
+
+func (b *bitBucketCloud) FileURL(webUrl, ref, rel string) (string, error) {
+	return fmt.Sprintf("%s/src/%s/%s", webUrl, ref, rel), nil
+}
+
+type bitBucketDatacenter struct {
+	base
+}
+
+var bitBucketDatacenterRefs = refPatterns{pull: "/pull-requests/", remoteRef: "refs/pull-requests/%d/from", localBranch: "pr-%d"}
+
+// bitBucketDatacenterSshPort is the port bitbucket data center serves ssh on
+const bitBucketDatacenterSshPort = 7999
+
+// ParseBrowserURL returns the repo path used in the clone urls of bitbucket data center,
+// ex: /projects/KEY/repos/slug/browse returns key/slug and /users/jdoe/repos/slug/browse returns ~jdoe/slug
+func (b *bitBucketDatacenter) ParseBrowserURL(path string) (string, *Ref, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 4 || parts[2] != "repos" || (parts[0] != "projects" && parts[0] != "users") {
+		return "", nil, errors.Errorf("%s is not a bitbucket data center repo url, expecting /projects/<key>/repos/<slug>", path)
+	}
+	repoPath := strings.ToLower(parts[1]) + "/" + parts[3]
+	if parts[0] == "users" {
+		repoPath = "~" + repoPath
+	}
+	ref, err := bitBucketDatacenterRefs.parse(path)
+	return repoPath, ref, err
+}
+
+// ParseCloneURL strips the /scm prefix of http clone urls
+func (b *bitBucketDatacenter) ParseCloneURL(path string) (string, error) {
+	return strings.TrimSuffix(strings.TrimPrefix(path, "scm/"), ".git"), nil
+}
+
+func (b *bitBucketDatacenter) SshDefaults() (string, int) {
+	return config.DefaultSshUser, bitBucketDatacenterSshPort
+}
+
+// HttpCloneURL returns the http clone url, which bitbucket data center serves under /scm
+func (b *bitBucketDatacenter) HttpCloneURL(d *CloneURLData) string {
+	return fmt.Sprintf("%s://%s/scm/%s.git", d.Scheme, d.Hostname, d.RepoPath)
+}
+
+func (b *bitBucketDatacenter) RemURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/tree/%s", webUrl, branch), nil
+}
+
+func (b *bitBucketDatacenter) PrsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/pull-requests", webUrl), nil
+}
+
+func (b *bitBucketDatacenter) BranchesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/branches", webUrl), nil
+}
+
+func (b *bitBucketDatacenter) CommitsURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/commits/%s", webUrl, branch), nil
+}
+
+func (b *bitBucketDatacenter) TagsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/tags", webUrl), nil
+}
+
+func (b *bitBucketDatacenter) FileURL(webUrl, ref, rel string) (string, error) {
+	return fmt.Sprintf("%s/src/%s/%s", webUrl, ref, rel), nil
+}
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"bytes"
+	neturl "net/url"
+	"regexp"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+// builtins are the names of the providers gitr supports natively, custom providers can not replace them
+var builtins = make(map[config.ScmProvider]bool)
+
+func init() {
+	for name := range factories {
+		builtins[name] = true
+	}
+	config.AddLoadHook(RegisterCustom)
+}
+
+// custom is a provider defined in the config file. Urls without a template are left to the base provider of the
+// custom provider, which is a provider without pages when the custom provider has no base.
+type custom struct {
+	Provider
+	name     config.ScmProvider
+	repoPath *regexp.Regexp
+	web      *template.Template
+	pages    map[Page]*template.Template
+	// repo is the scheme, hostname and repo path of the pages of a provider returned by ForRepo
+	repo *customPageData
+}
+
+// pageless is the base provider of custom providers without one, none of its pages are supported
+type pageless struct {
+	base
+}
+
+func (p *pageless) ParseBrowserURL(path string) (string, *Ref, error) {
+	repoPath, err := ownerRepoPath(path, p.name)
+	return repoPath, nil, err
+}
+
+// customPageData is what the url templates of custom providers are executed with
+type customPageData struct {
+	Scheme   string
+	Hostname string
+	RepoPath string
+	WebUrl   string
+	Branch   string
+	Ref      string
+	Path     string
+}
+
+// customNames are the names of the custom providers registered from the last config
+var customNames = make(map[config.ScmProvider]bool)
+
+// RegisterCustom registers the custom providers of the config, replacing the ones registered from a previous config.
+// Custom providers of a previous config that are no longer in the config are unregistered.
+func RegisterCustom(cfg *config.GitrConfig) error {
+	registered := make(map[config.ScmProvider]Factory)
+	for _, cp := range cfg.Providers {
+		f, err := newCustomFactory(cp)
+		if err != nil {
+			return errors.Wrapf(err, "invalid provider %s", cp.Name)
+		}
+		registered[cp.Name] = f
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for name := range customNames {
+		delete(factories, name)
+		delete(customNames, name)
+	}
+	for name, f := range registered {
+		factories[name] = f
+		customNames[name] = true
+	}
+	return nil
+}
+
+func newCustomFactory(cp *config.CustomProvider) (Factory, error) {
+	if cp.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if builtins[cp.Name] {
+		return nil, errors.Errorf("%s is a built-in provider", cp.Name)
+	}
+	if cp.Base != "" && !builtins[cp.Base] {
+		return nil, errors.Errorf("base %s is not a built-in provider", cp.Base)
+	}
+	if cp.Base == "" && cp.RepoPathRegex == "" {
+		return nil, errors.New("repoPathRegex is required for providers without a base")
+	}
+	var repoPath *regexp.Regexp
+	if cp.RepoPathRegex != "" {
+		var err error
+		if repoPath, err = regexp.Compile(cp.RepoPathRegex); err != nil {
+			return nil, errors.Wrapf(err, "failed to compile repoPathRegex %s", cp.RepoPathRegex)
+		}
+		if repoPath.NumSubexp() < 1 {
+			return nil, errors.Errorf("repoPathRegex %s has no group for the repo path", cp.RepoPathRegex)
+		}
+	}
+	urls := cp.Urls
+	if urls == nil {
+		urls = &config.CustomProviderUrls{}
+	}
+	web, err := parseUrlTemplate("web", urls.Web)
+	if err != nil {
+		return nil, err
+	}
+	pages := make(map[Page]*template.Template)
+	for page, text := range map[Page]string{
+		PageRem:       urls.Branch,
+		PagePrs:       urls.Prs,
+		PagePipelines: urls.Pipelines,
+		PageIssues:    urls.Issues,
+		PageBranches:  urls.Branches,
+		PageCommits:   urls.Commits,
+		PageTags:      urls.Tags,
+		PageReleases:  urls.Releases,
+		PageFile:      urls.File,
+	} {
+		tmpl, err := parseUrlTemplate(string(page), text)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl != nil {
+			pages[page] = tmpl
+		}
+	}
+	var baseFactory Factory
+	if cp.Base != "" {
+		mu.RLock()
+		baseFactory = factories[cp.Base]
+		mu.RUnlock()
+	}
+	return func(s *config.ScmHost) Provider {
+		c := &custom{name: cp.Name, repoPath: repoPath, web: web, pages: pages}
+		c.Provider = &pageless{base{cp.Name}}
+		if baseFactory != nil {
+			c.Provider = baseFactory(s)
+		}
+		return c
+	}, nil
+}
+
+// parseUrlTemplate parses the url template and checks that it only uses known fields, nil is returned for an empty text.
+// Only the hermetic sprig functions are available, so templates can not read env vars into urls.
+func parseUrlTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Funcs(sprig.HermeticTxtFuncMap()).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s url template %s", name, text)
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, &customPageData{}); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s url template %s", name, text)
+	}
+	return tmpl, nil
+}
+
+func (c *custom) Name() config.ScmProvider {
+	return c.name
+}
+
+func (c *custom) ParseBrowserURL(path string) (string, *Ref, error) {
+	if c.repoPath == nil {
+		return c.Provider.ParseBrowserURL(path)
+	}
+	m := c.repoPath.FindStringSubmatch(path)
+	if m == nil || m[1] == "" {
+		return "", nil, errors.Errorf("%s does not match the repoPathRegex of the %s provider", path, c.name)
+	}
+	return m[1], nil, nil
+}
+
+func (c *custom) RepoWebURL(scheme config.HttpScheme, host, repoPath string) string {
+	if c.web == nil {
+		return c.Provider.RepoWebURL(scheme, host, repoPath)
+	}
+	var b bytes.Buffer
+	if err := c.web.Execute(&b, &customPageData{Scheme: string(scheme), Hostname: host, RepoPath: repoPath}); err != nil {
+		return c.Provider.RepoWebURL(scheme, host, repoPath)
+	}
+	return b.String()
+}
+
+// page executes the template of the page, or returns the url of the base provider when the page has no template
+func (c *custom) page(page Page, webUrl, ref, rel string, fallback func() (string, error)) (string, error) {
+	tmpl, ok := c.pages[page]
+	if !ok {
+		pageUrl, err := fallback()
+		var notSupported *NotSupportedErr
+		if errors.As(err, &notSupported) {
+			return "", &NotSupportedErr{Provider: c.name, Page: page}
+		}
+		return pageUrl, err
+	}
+	d := &customPageData{WebUrl: webUrl, Branch: ref, Ref: ref, Path: rel}
+	if c.repo != nil {
+		d.Scheme, d.Hostname, d.RepoPath = c.repo.Scheme, c.repo.Hostname, c.repo.RepoPath
+	} else if u, err := neturl.Parse(webUrl); err == nil {
+		// without the repo of ForRepo, the repo path is only known when the web url has the shape of browser urls
+		d.Scheme, d.Hostname = u.Scheme, u.Host
+		d.RepoPath, _, _ = c.ParseBrowserURL(u.Path)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, d); err != nil {
+		return "", errors.Wrapf(err, "failed to render the %s url of the %s provider", page, c.name)
+	}
+	return b.String(), nil
+}
+
+func (c *custom) RemURL(webUrl, branch string) (string, error) {
+	return c.page(PageRem, webUrl, branch, "", func() (string, error) { return c.Provider.RemURL(webUrl, branch) })
+}
+
+func (c *custom) PrsURL(webUrl string) (string, error) {
+	return c.page(PagePrs, webUrl, "", "", func() (string, error) { return c.Provider.PrsURL(webUrl) })
+}
+
+func (c *custom) BranchesURL(webUrl string) (string, error) {
+	return c.page(PageBranches, webUrl, "", "", func() (string, error) { return c.Provider.BranchesURL(webUrl) })
+}
+
+func (c *custom) CommitsURL(webUrl, branch string) (string, error) {
+	return c.page(PageCommits, webUrl, branch, "", func() (string, error) { return c.Provider.CommitsURL(webUrl, branch) })
+}
+
+func (c *custom) TagsURL(webUrl string) (string, error) {
+	return c.page(PageTags, webUrl, "", "", func() (string, error) { return c.Provider.TagsURL(webUrl) })
+}
+
+func (c *custom) IssuesURL(webUrl string) (string, error) {
+	return c.page(PageIssues, webUrl, "", "", func() (string, error) { return c.Provider.IssuesURL(webUrl) })
+}
+
+func (c *custom) ReleasesURL(webUrl string) (string, error) {
+	return c.page(PageReleases, webUrl, "", "", func() (string, error) { return c.Provider.ReleasesURL(webUrl) })
+}
+
+func (c *custom) PipelinesURL(webUrl string) (string, error) {
+	return c.page(PagePipelines, webUrl, "", "", func() (string, error) { return c.Provider.PipelinesURL(webUrl) })
+}
+
+func (c *custom) FileURL(webUrl, ref, rel string) (string, error) {
+	return c.page(PageFile, webUrl, ref, rel, func() (string, error) { return c.Provider.FileURL(webUrl, ref, rel) })
+}
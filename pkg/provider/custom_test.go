@@ -0,0 +1,157 @@
+package provider_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
+)
+
+func TestRegisterCustom(t *testing.T) {
+	cfg := &config.GitrConfig{Providers: []*config.CustomProvider{
+		{
+			Name:          "forge",
+			RepoPathRegex: `^/repos/([^/]+/[^/]+)`,
+			Urls: &config.CustomProviderUrls{
+				Web:    "{{.Scheme}}://{{.Hostname}}/repos/{{.RepoPath}}",
+				Prs:    "{{.WebUrl}}/reviews?state=open",
+				Branch: "{{.WebUrl}}/browse?at={{.Branch | urlquery}}",
+				File:   "{{.WebUrl}}/browse/{{.Path}}?at={{.Ref}}",
+				Issues: "https://tickets.example.com/search?repo={{.RepoPath | replace \"/\" \"-\"}}",
+			},
+		},
+		{
+			Name:          "gitlab-mirror",
+			Base:          config.GitLab,
+			RepoPathRegex: `^/mirror/(.+?)(?:/-/.*)?$`,
+			Urls: &config.CustomProviderUrls{
+				Web:       "{{.Scheme}}://{{.Hostname}}/mirror/{{.RepoPath}}",
+				Pipelines: "https://ci.example.com/{{.Hostname}}/{{.RepoPath}}",
+			},
+		},
+	}}
+	if err := provider.RegisterCustom(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forge, err := provider.ForHost(&config.ScmHost{Hostname: "forge.example.com", Provider: "forge"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	webUrl := forge.RepoWebURL(config.Https, "forge.example.com", "team/svc")
+
+	t.Run("browser urls should be parsed with the repo path regex", func(t *testing.T) {
+		repoPath, ref, err := forge.ParseBrowserURL("/repos/team/svc/browse/README.md")
+		if err != nil || repoPath != "team/svc" || ref != nil {
+			t.Errorf("unexpected repo path %s, ref %v and error %v", repoPath, ref, err)
+		}
+		if _, _, err := forge.ParseBrowserURL("/team/svc"); err == nil {
+			t.Errorf("expecting an error for a path not matching the regex")
+		}
+	})
+	t.Run("urls should be rendered from the templates", func(t *testing.T) {
+		if webUrl != "https://forge.example.com/repos/team/svc" {
+			t.Errorf("unexpected web url %s", webUrl)
+		}
+		checks := []struct {
+			page     func() (string, error)
+			expected string
+		}{
+			{func() (string, error) { return forge.PrsURL(webUrl) }, "https://forge.example.com/repos/team/svc/reviews?state=open"},
+			{func() (string, error) { return forge.RemURL(webUrl, "feat/x") }, "https://forge.example.com/repos/team/svc/browse?at=feat%2Fx"},
+			{func() (string, error) { return forge.FileURL(webUrl, "main", "docs/a.md") }, "https://forge.example.com/repos/team/svc/browse/docs/a.md?at=main"},
+			{func() (string, error) { return forge.IssuesURL(webUrl) }, "https://tickets.example.com/search?repo=team-svc"},
+		}
+		for _, c := range checks {
+			if got, err := c.page(); err != nil || got != c.expected {
+				t.Errorf("expecting %s but got %s, %v", c.expected, got, err)
+			}
+		}
+	})
+	t.Run("pages without a template should not be supported without a base", func(t *testing.T) {
+		_, err := forge.PipelinesURL(webUrl)
+		var notSupported *provider.NotSupportedErr
+		if !errors.As(err, &notSupported) || notSupported.Provider != "forge" {
+			t.Errorf("expecting a not supported error of the forge provider but got %v", err)
+		}
+	})
+	t.Run("urls without a template should fall back to the base provider", func(t *testing.T) {
+		mirror, err := provider.Get("gitlab-mirror")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		mirrorUrl := mirror.RepoWebURL(config.Https, "git.example.com", "group/svc")
+		if got, _ := mirror.PrsURL(mirrorUrl); got != "https://git.example.com/mirror/group/svc/-/merge_requests" {
+			t.Errorf("unexpected prs url %s", got)
+		}
+		if got, _ := mirror.PipelinesURL(mirrorUrl); got != "https://ci.example.com/git.example.com/group/svc" {
+			t.Errorf("unexpected pipelines url %s", got)
+		}
+		if repoPath, _, _ := mirror.ParseBrowserURL("/mirror/group/svc/-/tree/main"); repoPath != "group/svc" {
+			t.Errorf("unexpected repo path %s", repoPath)
+		}
+		if repoPath, _ := mirror.ParseCloneURL("group/svc.git"); repoPath != "group/svc" {
+			t.Errorf("unexpected clone url repo path %s", repoPath)
+		}
+	})
+}
+
+func TestCustomForRepo(t *testing.T) {
+	cfg := &config.GitrConfig{Providers: []*config.CustomProvider{{
+		Name:          "forge",
+		RepoPathRegex: `^/repos/([^/]+/[^/]+)`,
+		Urls: &config.CustomProviderUrls{
+			Web:    "https://web.example.com/r/{{.RepoPath}}",
+			Issues: "https://tickets.example.com/{{.Hostname}}/{{.RepoPath}}",
+		},
+	}}}
+	if err := provider.RegisterCustom(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := &config.ScmHost{Hostname: "forge.example.com", Provider: "forge"}
+	t.Run("pages should get the repo path from the caller when the web url is not a browser url", func(t *testing.T) {
+		forge, err := provider.ForRepo(s, "team/svc")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		webUrl := forge.RepoWebURL(config.Https, s.Hostname, "team/svc")
+		if got, err := forge.IssuesURL(webUrl); err != nil || got != "https://tickets.example.com/forge.example.com/team/svc" {
+			t.Errorf("unexpected issues url %s, %v", got, err)
+		}
+	})
+	t.Run("providers removed from the config should be unregistered", func(t *testing.T) {
+		if err := provider.RegisterCustom(&config.GitrConfig{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := provider.ForHost(s); err == nil {
+			t.Errorf("expecting the forge provider to be unregistered")
+		}
+		if _, err := provider.Get(config.GitHub); err != nil {
+			t.Errorf("expecting built-in providers to be kept but got %v", err)
+		}
+	})
+}
+
+func TestRegisterCustomErrors(t *testing.T) {
+	var tests = []struct {
+		name string
+		cp   *config.CustomProvider
+	}{
+		{"missing name", &config.CustomProvider{RepoPathRegex: "^/(.+)"}},
+		{"built-in name", &config.CustomProvider{Name: config.GitHub, RepoPathRegex: "^/(.+)"}},
+		{"unknown base", &config.CustomProvider{Name: "forge", Base: "unknown"}},
+		{"missing regex and base", &config.CustomProvider{Name: "forge"}},
+		{"regex without a group", &config.CustomProvider{Name: "forge", RepoPathRegex: "^/.+"}},
+		{"invalid regex", &config.CustomProvider{Name: "forge", RepoPathRegex: "^/(.+"}},
+		{"unknown template field", &config.CustomProvider{Name: "forge", RepoPathRegex: "^/(.+)", Urls: &config.CustomProviderUrls{Prs: "{{.Unknown}}"}}},
+		{"invalid template", &config.CustomProvider{Name: "forge", RepoPathRegex: "^/(.+)", Urls: &config.CustomProviderUrls{Prs: "{{.WebUrl"}}},
+		{"template reading env vars", &config.CustomProvider{Name: "forge", RepoPathRegex: "^/(.+)", Urls: &config.CustomProviderUrls{Prs: `{{.WebUrl}}?token={{env "GITHUB_TOKEN"}}`}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+" should fail", func(t *testing.T) {
+			if err := provider.RegisterCustom(&config.GitrConfig{Providers: []*config.CustomProvider{tc.cp}}); err == nil {
+				t.Errorf("expecting an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+// gerrit links branches and files to the gitiles or gitweb repo browser configured for the host
+type gerrit struct {
+	base
+	browser    config.GerritBrowser
+	browserUrl string
+}
+
+// gerritSshPort is the port gerrit serves ssh on
+const gerritSshPort = 29418
+
+// gerritProjectPath is the path of the project page in the gerrit web url of a repo, ex: https://host/admin/repos/platform/build
+const gerritProjectPath = "/admin/repos/"
+
+func newGerrit(s *config.ScmHost) Provider {
+	return &gerrit{base: base{config.Gerrit}, browser: s.Browser, browserUrl: strings.TrimSuffix(s.BrowserUrl, "/")}
+}
+
+func (g *gerrit) ParseBrowserURL(path string) (string, *Ref, error) {
+	repoPath, err := gerritRepoPath(path)
+	return repoPath, nil, err
+}
+
+func (g *gerrit) ParseCloneURL(path string) (string, error) {
+	return gerritRepoPath(path)
+}
+
+// gerritRepoPath returns the project from the path of a gerrit clone or browser url, ex: platform/build for
+// /a/platform/build, /c/platform/build/+/12345, /admin/repos/platform/build,branches and /plugins/gitiles/platform/build/+/refs/heads/main
+func gerritRepoPath(path string) (string, error) {
+	path = strings.Trim(path, "/")
+	for _, prefix := range []string{"a/", "c/", "admin/repos/", "plugins/gitiles/"} {
+		if strings.HasPrefix(path, prefix) {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	if idx := strings.Index(path, "/+/"); idx != -1 {
+		path = path[:idx]
+	}
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/+"), ",branches")
+	path = strings.TrimSuffix(strings.TrimSuffix(path, ",tags"), ".git")
+	if path == "" {
+		return "", errors.Errorf("gerrit url does not point at a project")
+	}
+	return path, nil
+}
+
+// SshDefaults leaves the user to the ssh config, gerrit authenticates personal users
+func (g *gerrit) SshDefaults() (string, int) {
+	return "", gerritSshPort
+}
+
+func (g *gerrit) SshCloneURL(d *CloneURLData) string {
+	if d.SshUser == "" {
+		return fmt.Sprintf("ssh://%s:%d/%s", d.Hostname, d.SshPort, d.RepoPath)
+	}
+	return fmt.Sprintf("ssh://%s@%s:%d/%s", d.SshUser, d.Hostname, d.SshPort, d.RepoPath)
+}
+
+func (g *gerrit) RepoWebURL(scheme config.HttpScheme, host, repoPath string) string {
+	return fmt.Sprintf("%s://%s%s%s", scheme, host, gerritProjectPath, repoPath)
+}
+
+// splitWebUrl returns the base url of the gerrit host and the project of a gerrit web url
+func (g *gerrit) splitWebUrl(webUrl string) (base, project string) {
+	if idx := strings.Index(webUrl, gerritProjectPath); idx != -1 {
+		return webUrl[:idx], webUrl[idx+len(gerritProjectPath):]
+	}
+	return webUrl, ""
+}
+
+// queryUrl returns the url of the gerrit change search for the project, ex: https://host/q/project:platform/build+status:open
+func (g *gerrit) queryUrl(webUrl, query string) string {
+	base, project := g.splitWebUrl(webUrl)
+	return fmt.Sprintf("%s/q/project:%s+%s", base, project, query)
+}
+
+// browseUrl returns the url of the branch, or of the file at rel in the branch, in the repo browser of the host.
+//
+//	gitiles : <browserUrl>/<project>/+/refs/heads/<branch>/<rel>
+//	gitweb  : <browserUrl>?p=<project>.git;a=shortlog;h=refs/heads/<branch>
+//	          <browserUrl>?p=<project>.git;a=blob;f=<rel>;hb=refs/heads/<branch>
+func (g *gerrit) browseUrl(webUrl, branch, rel string) string {
+	base, project := g.splitWebUrl(webUrl)
+	browserUrl := g.browserUrl
+	if g.browser == config.GerritBrowserGitweb {
+		if browserUrl == "" {
+			browserUrl = base + "/gitweb"
+		}
+		if rel == "" {
+			return fmt.Sprintf("%s?p=%s.git;a=shortlog;h=refs/heads/%s", browserUrl, project, branch)
+		}
+		return fmt.Sprintf("%s?p=%s.git;a=blob;f=%s;hb=refs/heads/%s", browserUrl, project, rel, branch)
+	}
+	if browserUrl == "" {
+		browserUrl = base + "/plugins/gitiles"
+	}
+	if rel == "" {
+		return fmt.Sprintf("%s/%s/+/refs/heads/%s", browserUrl, project, branch)
+	}
+	return fmt.Sprintf("%s/%s/+/refs/heads/%s/%s", browserUrl, project, branch, rel)
+}
+
+func (g *gerrit) RemURL(webUrl, branch string) (string, error) {
+	return g.browseUrl(webUrl, branch, ""), nil
+}
+
+func (g *gerrit) PrsURL(webUrl string) (string, error) {
+	return g.queryUrl(webUrl, "status:open"), nil
+}
+
+func (g *gerrit) BranchesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s,branches", webUrl), nil
+}
+
+func (g *gerrit) CommitsURL(webUrl, branch string) (string, error) {
+	return g.queryUrl(webUrl, "branch:"+branch), nil
+}
+
+func (g *gerrit) TagsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s,tags", webUrl), nil
+}
+
+func (g *gerrit) FileURL(webUrl, ref, rel string) (string, error) {
+	return g.browseUrl(webUrl, ref, rel), nil
+}
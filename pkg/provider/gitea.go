@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"fmt"
+)
+
+// gitea also covers forgejo, which kept the urls of gitea
+type gitea struct {
+	base
+}
+
+var giteaRefs = refPatterns{names: []string{"/src/branch/", "/src/tag/", "/commits/branch/"}, pull: "/pulls/", remoteRef: "refs/pull/%d/head", localBranch: "pr-%d"}
+
+func (g *gitea) ParseBrowserURL(path string) (string, *Ref, error) {
+	repoPath, err := ownerRepoPath(path, g.name)
+	if err != nil {
+		return "", nil, err
+	}
+	ref, err := giteaRefs.parse(path)
+	return repoPath, ref, err
+}
+
+func (g *gitea) RemURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/src/branch/%s", webUrl, branch), nil
+}
+
+func (g *gitea) PrsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/pulls", webUrl), nil
+}
+
+func (g *gitea) BranchesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/branches", webUrl), nil
+}
+
+func (g *gitea) CommitsURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/commits/branch/%s", webUrl, branch), nil
+}
+
+func (g *gitea) TagsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/tags", webUrl), nil
+}
+
+func (g *gitea) IssuesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/issues", webUrl), nil
+}
+
+func (g *gitea) ReleasesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/releases", webUrl), nil
+}
+
+func (g *gitea) PipelinesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/actions", webUrl), nil
+}
+
+func (g *gitea) FileURL(webUrl, ref, rel string) (string, error) {
+	return fmt.Sprintf("%s/src/branch/%s/%s", webUrl, ref, rel), nil
+}
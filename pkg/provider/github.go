@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+type gitHub struct {
+	base
+}
+
+var gitHubRefs = refPatterns{names: []string{"/tree/", "/blob/", "/commits/"}, pull: "/pull/", remoteRef: "refs/pull/%d/head", localBranch: "pr-%d"}
+
+func (g *gitHub) ParseBrowserURL(path string) (string, *Ref, error) {
+	repoPath := strings.TrimPrefix(path, "/")
+	// GitHub web URL patterns that should be stripped to extract owner/repo
+	for _, pattern := range []string{"/tree/", "/blob/", "/commits/", "/pull/", "/issues/", "/compare/"} {
+		if idx := strings.Index(repoPath, pattern); idx != -1 {
+			repoPath = repoPath[:idx]
+			break
+		}
+	}
+	ref, err := gitHubRefs.parse(path)
+	return repoPath, ref, err
+}
+
+func (g *gitHub) RemURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/tree/%s", webUrl, branch), nil
+}
+
+func (g *gitHub) PrsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/pulls", webUrl), nil
+}
+
+func (g *gitHub) BranchesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/branches", webUrl), nil
+}
+
+func (g *gitHub) CommitsURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/commits/%s", webUrl, branch), nil
+}
+
+func (g *gitHub) TagsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/tags", webUrl), nil
+}
+
+func (g *gitHub) IssuesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/issues", webUrl), nil
+}
+
+func (g *gitHub) ReleasesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/releases", webUrl), nil
+}
+
+func (g *gitHub) PipelinesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/actions", webUrl), nil
+}
+
+func (g *gitHub) FileURL(webUrl, ref, rel string) (string, error) {
+	return fmt.Sprintf("%s/blob/%s/%s", webUrl, ref, rel), nil
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+type gitLab struct {
+	base
+}
+
+var gitLabRefs = refPatterns{names: []string{"/-/tree/", "/-/blob/", "/-/commits/"}, pull: "/-/merge_requests/", remoteRef: "refs/merge-requests/%d/head", localBranch: "mr-%d"}
+
+// ParseBrowserURL returns the path of the project, which may be nested in subgroups, up to the /-/ separator of project pages
+func (g *gitLab) ParseBrowserURL(path string) (string, *Ref, error) {
+	repoPath := strings.TrimPrefix(path, "/")
+	if idx := strings.Index(repoPath, "/-/"); idx != -1 {
+		repoPath = repoPath[:idx]
+	}
+	ref, err := gitLabRefs.parse(path)
+	return repoPath, ref, err
+}
+
+func (g *gitLab) RemURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/-/tree/%s", webUrl, branch), nil
+}
+
+func (g *gitLab) PrsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/-/merge_requests", webUrl), nil
+}
+
+func (g *gitLab) BranchesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/-/branches", webUrl), nil
+}
+
+func (g *gitLab) CommitsURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/-/commits/%s", webUrl, branch), nil
+}
+
+func (g *gitLab) TagsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/-/tags", webUrl), nil
+}
+
+func (g *gitLab) IssuesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/-/issues", webUrl), nil
+}
+
+func (g *gitLab) ReleasesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/-/releases", webUrl), nil
+}
+
+func (g *gitLab) PipelinesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/-/pipelines", webUrl), nil
+}
+
+func (g *gitLab) FileURL(webUrl, ref, rel string) (string, error) {
+	return fmt.Sprintf("%s/-/blob/%s/%s", webUrl, ref, rel), nil
+}
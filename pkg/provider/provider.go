@@ -0,0 +1,205 @@
+// Package provider holds the url conventions of the scm providers supported by gitr: how browser and clone urls
+// map to repo paths, what the default clone urls are and where the web pages of a repo live.
+// Adding a provider is a matter of implementing Provider and adding it to the registry.
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+// Page is a web page of a repo opened by gitr
+type Page string
+
+const (
+	PageRem       Page = "branch"
+	PagePrs       Page = "pull requests"
+	PageBranches  Page = "branches"
+	PageCommits   Page = "commits"
+	PageTags      Page = "tags"
+	PageIssues    Page = "issues"
+	PageReleases  Page = "releases"
+	PagePipelines Page = "pipelines"
+	PageFile      Page = "file"
+)
+
+// Provider knows the urls of an scm provider. Pages the provider does not have return a *NotSupportedErr.
+type Provider interface {
+	Name() config.ScmProvider
+	// ParseBrowserURL returns the repo path, and the ref when there is one, from the part of a browser url
+	// following the hostname, ex: /owner/repo/tree/main
+	ParseBrowserURL(path string) (repoPath string, ref *Ref, err error)
+	// ParseCloneURL returns the repo path from the path of an ssh or http clone url, ex: owner/repo.git
+	ParseCloneURL(path string) (string, error)
+	// SshDefaults returns the ssh user and port of the clone urls of hosts that do not configure them
+	SshDefaults() (user string, port int)
+	SshCloneURL(d *CloneURLData) string
+	HttpCloneURL(d *CloneURLData) string
+	RepoWebURL(scheme config.HttpScheme, host, repoPath string) string
+	RemURL(webUrl, branch string) (string, error)
+	PrsURL(webUrl string) (string, error)
+	BranchesURL(webUrl string) (string, error)
+	CommitsURL(webUrl, branch string) (string, error)
+	TagsURL(webUrl string) (string, error)
+	IssuesURL(webUrl string) (string, error)
+	ReleasesURL(webUrl string) (string, error)
+	PipelinesURL(webUrl string) (string, error)
+	// FileURL returns the url of the file at rel, a forward slash path inside the repo, on the branch or commit ref
+	FileURL(webUrl, ref, rel string) (string, error)
+}
+
+// CloneURLData is what clone urls are built from, and the data the clone url templates of a host are executed with
+type CloneURLData struct {
+	Hostname string
+	RepoPath string
+	SshUser  string
+	SshPort  int
+	Scheme   config.HttpScheme
+}
+
+// NotSupportedErr is returned for the web pages a provider does not have
+type NotSupportedErr struct {
+	Provider config.ScmProvider
+	Page     Page
+}
+
+func (e *NotSupportedErr) Error() string {
+	return fmt.Sprintf("%s pages are not supported by the %s provider", e.Page, e.Provider)
+}
+
+// Factory creates the provider of an scm host, which lets providers read settings of the host
+type Factory func(s *config.ScmHost) Provider
+
+var (
+	mu        sync.RWMutex
+	factories = map[config.ScmProvider]Factory{
+		config.GitHub:              func(*config.ScmHost) Provider { return &gitHub{base{config.GitHub}} },
+		config.GitLab:              func(*config.ScmHost) Provider { return &gitLab{base{config.GitLab}} },
+		config.BitBucketCloud:      func(*config.ScmHost) Provider { return &bitBucketCloud{base{config.BitBucketCloud}} },
+		config.BitBucketDatacenter: func(*config.ScmHost) Provider { return &bitBucketDatacenter{base{config.BitBucketDatacenter}} },
+		config.Gitea:               func(*config.ScmHost) Provider { return &gitea{base{config.Gitea}} },
+		config.AzureDevOps:         func(*config.ScmHost) Provider { return &azureDevOps{base{config.AzureDevOps}} },
+		config.Gerrit:              newGerrit,
+		config.SourceHut:           func(*config.ScmHost) Provider { return &sourceHut{base{config.SourceHut}} },
+	}
+)
+
+// Register adds the provider created by the factory under the name, replacing any provider with the same name
+func Register(name config.ScmProvider, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[name] = f
+}
+
+// ForHost returns the provider of the scm host
+func ForHost(s *config.ScmHost) (Provider, error) {
+	mu.RLock()
+	f, ok := factories[s.Provider]
+	mu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("provider %s not supported, expecting one of %s", s.Provider, strings.Join(Names(), ", "))
+	}
+	return f(s), nil
+}
+
+// ForRepo returns the provider of the scm host for the pages of the repo at repoPath.
+// The page templates of custom providers get the repo path from here,
+// since it can not be parsed back from web urls that do not have the shape of browser urls.
+func ForRepo(s *config.ScmHost, repoPath string) (Provider, error) {
+	p, err := ForHost(s)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := p.(*custom); ok {
+		scheme := s.Scheme
+		if scheme == "" {
+			scheme = config.Https
+		}
+		c.repo = &customPageData{Scheme: string(scheme), Hostname: s.Hostname, RepoPath: repoPath}
+	}
+	return p, nil
+}
+
+// Get returns the provider with the name, with the defaults of the provider for host settings
+func Get(name config.ScmProvider) (Provider, error) {
+	return ForHost(&config.ScmHost{Provider: name})
+}
+
+// Names returns the names of the registered providers, sorted
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// base implements the parts of Provider shared by most providers. Every page is not supported.
+type base struct {
+	name config.ScmProvider
+}
+
+func (b base) Name() config.ScmProvider {
+	return b.name
+}
+
+func (b base) notSupported(page Page) (string, error) {
+	return "", &NotSupportedErr{Provider: b.name, Page: page}
+}
+
+func (b base) ParseCloneURL(path string) (string, error) {
+	return strings.TrimSuffix(path, ".git"), nil
+}
+
+func (b base) SshDefaults() (string, int) {
+	return config.DefaultSshUser, DefaultSshPort
+}
+
+// SshCloneURL returns the scp-like url user@host:path.git, and an ssh:// url when the port is not the default one
+func (b base) SshCloneURL(d *CloneURLData) string {
+	if d.SshPort != DefaultSshPort {
+		return fmt.Sprintf("ssh://%s@%s:%d/%s.git", d.SshUser, d.Hostname, d.SshPort, d.RepoPath)
+	}
+	return fmt.Sprintf("%s@%s:%s.git", d.SshUser, d.Hostname, d.RepoPath)
+}
+
+func (b base) HttpCloneURL(d *CloneURLData) string {
+	return fmt.Sprintf("%s://%s/%s.git", d.Scheme, d.Hostname, d.RepoPath)
+}
+
+func (b base) RepoWebURL(scheme config.HttpScheme, host, repoPath string) string {
+	return fmt.Sprintf("%s://%s/%s", scheme, host, repoPath)
+}
+
+func (b base) RemURL(string, string) (string, error)     { return b.notSupported(PageRem) }
+func (b base) PrsURL(string) (string, error)             { return b.notSupported(PagePrs) }
+func (b base) BranchesURL(string) (string, error)        { return b.notSupported(PageBranches) }
+func (b base) CommitsURL(string, string) (string, error) { return b.notSupported(PageCommits) }
+func (b base) TagsURL(string) (string, error)            { return b.notSupported(PageTags) }
+func (b base) IssuesURL(string) (string, error)          { return b.notSupported(PageIssues) }
+func (b base) ReleasesURL(string) (string, error)        { return b.notSupported(PageReleases) }
+func (b base) PipelinesURL(string) (string, error)       { return b.notSupported(PagePipelines) }
+func (b base) FileURL(string, string, string) (string, error) {
+	return b.notSupported(PageFile)
+}
+
+// DefaultSshPort is left out of ssh clone urls
+const DefaultSshPort = 22
+
+// ownerRepoPath returns owner/repo from the part of a browser url following the hostname, for providers
+// without nested groups, ex: /workspace/repo/src/main/README.md on bitbucket cloud and /owner/repo/pulls/3 on gitea
+func ownerRepoPath(path string, p config.ScmProvider) (string, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		return "", errors.Errorf("%s is not a %s repo url", path, p)
+	}
+	return parts[0] + "/" + parts[1], nil
+}
@@ -0,0 +1,236 @@
+package provider_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
+)
+
+// pageTest is a web page url expected for the web url of a repo, an empty expectedUrl expects a *provider.NotSupportedErr
+type pageTest struct {
+	provider    config.ScmProvider
+	webUrl      string
+	expectedUrl string
+}
+
+func checkPageUrls(t *testing.T, tests []pageTest, page func(p provider.Provider, webUrl string) (string, error)) {
+	t.Helper()
+	for _, u := range tests {
+		p, err := provider.Get(u.provider)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		returnedUrl, err := page(p, u.webUrl)
+		if u.expectedUrl == "" {
+			var notSupported *provider.NotSupportedErr
+			if !errors.As(err, &notSupported) {
+				t.Errorf("expecting the page to not be supported by %s but got %s, %v", u.provider, returnedUrl, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", u.provider, err)
+		}
+		if returnedUrl != u.expectedUrl {
+			t.Errorf("expecting %s but got %s", u.expectedUrl, returnedUrl)
+		}
+	}
+}
+
+func TestRemUrls(t *testing.T) {
+	var urlTests = []pageTest{
+		{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/tree/master"},
+		{config.BitBucketCloud, "https://bitbucket.org/ramamohanraju/demo-project", "https://bitbucket.org/ramamohanraju/demo-project/branch/master"},
+		{config.Gitea, "https://gitea.example.com/team/svc", "https://gitea.example.com/team/svc/src/branch/master"},
+		{config.AzureDevOps, "https://dev.azure.com/org/project/_git/repo", "https://dev.azure.com/org/project/_git/repo?version=GBmaster"},
+		{config.Gerrit, "https://review.example.com/admin/repos/platform/build", "https://review.example.com/plugins/gitiles/platform/build/+/refs/heads/master"},
+		{config.SourceHut, "https://git.sr.ht/~user/repo", "https://git.sr.ht/~user/repo/tree/master"},
+	}
+	t.Run("validate remote urls", func(t *testing.T) {
+		checkPageUrls(t, urlTests, func(p provider.Provider, webUrl string) (string, error) {
+			return p.RemURL(webUrl, "master")
+		})
+	})
+	t.Run("branch names with slashes should be kept", func(t *testing.T) {
+		checkPageUrls(t, []pageTest{
+			{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/tree/feat/custom-branch"},
+		}, func(p provider.Provider, webUrl string) (string, error) {
+			return p.RemURL(webUrl, "feat/custom-branch")
+		})
+	})
+}
+
+func TestPrsUrls(t *testing.T) {
+	var urlTests = []pageTest{
+		{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/pulls"},
+		{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss", "https://gitlab.com/gitlab-org/gitlab-foss/-/merge_requests"},
+		{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api", "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api/-/merge_requests"},
+		{config.Gitea, "https://gitea.example.com/team/svc", "https://gitea.example.com/team/svc/pulls"},
+		{config.AzureDevOps, "https://dev.azure.com/org/project/_git/repo", "https://dev.azure.com/org/project/_git/repo/pullrequests"},
+		{config.Gerrit, "https://review.example.com/admin/repos/platform/build", "https://review.example.com/q/project:platform/build+status:open"},
+		{config.SourceHut, "https://git.sr.ht/~user/repo", "https://lists.sr.ht/~user/repo/patches"},
+	}
+	t.Run("validate mr/pr urls", func(t *testing.T) {
+		checkPageUrls(t, urlTests, provider.Provider.PrsURL)
+	})
+}
+
+func TestIssuesUrls(t *testing.T) {
+	var urlTests = []pageTest{
+		{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/issues"},
+		{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api", "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api/-/issues"},
+		{config.SourceHut, "https://git.sr.ht/~user/repo", "https://todo.sr.ht/~user/repo"},
+		{config.AzureDevOps, "https://dev.azure.com/org/project/_git/repo", "https://dev.azure.com/org/project/_workitems"},
+		{config.Gerrit, "https://review.example.com/admin/repos/platform/build", ""},
+		{config.BitBucketCloud, "https://bitbucket.org/ramamohanraju/demo-project", ""},
+	}
+	t.Run("validate issues urls", func(t *testing.T) {
+		checkPageUrls(t, urlTests, provider.Provider.IssuesURL)
+	})
+}
+
+func TestTagsUrls(t *testing.T) {
+	var urlTests = []pageTest{
+		{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/tags"},
+		{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api", "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api/-/tags"},
+	}
+	t.Run("validate tags urls", func(t *testing.T) {
+		checkPageUrls(t, urlTests, provider.Provider.TagsURL)
+	})
+}
+
+func TestReleasesUrls(t *testing.T) {
+	var urlTests = []pageTest{
+		{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/releases"},
+		{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api", "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api/-/releases"},
+		{config.Gitea, "https://gitea.example.com/team/svc", "https://gitea.example.com/team/svc/releases"},
+		{config.BitBucketDatacenter, "https://bitbucket.example.com/projects/KEY/repos/r", ""},
+		{config.SourceHut, "https://git.sr.ht/~user/repo", ""},
+	}
+	t.Run("validate releases urls", func(t *testing.T) {
+		checkPageUrls(t, urlTests, provider.Provider.ReleasesURL)
+	})
+}
+
+func TestPipelinesUrls(t *testing.T) {
+	var urlTests = []pageTest{
+		{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/actions"},
+		{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api", "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api/-/pipelines"},
+		{config.Gitea, "https://gitea.example.com/team/svc", "https://gitea.example.com/team/svc/actions"},
+		{config.AzureDevOps, "https://dev.azure.com/org/project/_git/repo", "https://dev.azure.com/org/project/_build"},
+		{config.SourceHut, "https://git.sr.ht/~user/repo", "https://builds.sr.ht/~user/repo"},
+		{config.Gerrit, "https://review.example.com/admin/repos/platform/build", ""},
+	}
+	t.Run("validate pipelines urls", func(t *testing.T) {
+		checkPageUrls(t, urlTests, provider.Provider.PipelinesURL)
+	})
+}
+
+func TestBranchesUrls(t *testing.T) {
+	var urlTests = []pageTest{
+		{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/branches"},
+		{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api", "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api/-/branches"},
+		{config.AzureDevOps, "https://dev.azure.com/org/project/_git/repo", "https://dev.azure.com/org/project/_git/repo/branches"},
+		{config.Gerrit, "https://review.example.com/admin/repos/platform/build", "https://review.example.com/admin/repos/platform/build,branches"},
+	}
+	t.Run("validate branches urls", func(t *testing.T) {
+		checkPageUrls(t, urlTests, provider.Provider.BranchesURL)
+	})
+}
+
+func TestCommitsUrls(t *testing.T) {
+	var urlTests = []struct {
+		pageTest
+		branch string
+	}{
+		{pageTest{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/commits/master"}, "master"},
+		{pageTest{config.GitHub, "https://github.com/swarupdonepudi/gitr", "https://github.com/swarupdonepudi/gitr/commits/feat/custom"}, "feat/custom"},
+		{pageTest{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api", "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api/-/commits/main"}, "main"},
+		{pageTest{config.GitLab, "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api", "https://gitlab.com/gitlab-org/gitlab-foss/gitlab-foss-api/-/commits/feat/custom"}, "feat/custom"},
+		{pageTest{config.Gitea, "https://gitea.example.com/team/svc", "https://gitea.example.com/team/svc/commits/branch/main"}, "main"},
+		{pageTest{config.AzureDevOps, "https://dev.azure.com/org/project/_git/repo", "https://dev.azure.com/org/project/_git/repo/commits?itemVersion=GBmain"}, "main"},
+		{pageTest{config.Gerrit, "https://review.example.com/admin/repos/platform/build", "https://review.example.com/q/project:platform/build+branch:main"}, "main"},
+		{pageTest{config.SourceHut, "https://git.sr.ht/~user/repo", "https://git.sr.ht/~user/repo/log/main"}, "main"},
+	}
+	t.Run("validate commits urls", func(t *testing.T) {
+		for _, u := range urlTests {
+			checkPageUrls(t, []pageTest{u.pageTest}, func(p provider.Provider, webUrl string) (string, error) {
+				return p.CommitsURL(webUrl, u.branch)
+			})
+		}
+	})
+}
+
+func TestFileUrls(t *testing.T) {
+	var urlTests = []pageTest{
+		{config.GitHub, "https://github.com/acme/repo", "https://github.com/acme/repo/blob/main/docs/readme.md"},
+		{config.GitLab, "https://gitlab.com/acme/repo", "https://gitlab.com/acme/repo/-/blob/main/docs/readme.md"},
+		{config.BitBucketCloud, "https://bitbucket.org/acme/repo", "https://bitbucket.org/acme/repo/src/main/docs/readme.md"},
+		{config.Gitea, "https://gitea.example.com/acme/repo", "https://gitea.example.com/acme/repo/src/branch/main/docs/readme.md"},
+		{config.AzureDevOps, "https://dev.azure.com/acme/proj/_git/repo", "https://dev.azure.com/acme/proj/_git/repo?path=/docs/readme.md&version=GBmain"},
+		{config.Gerrit, "https://review.example.com/admin/repos/platform/build", "https://review.example.com/plugins/gitiles/platform/build/+/refs/heads/main/docs/readme.md"},
+		{config.SourceHut, "https://git.sr.ht/~acme/repo", "https://git.sr.ht/~acme/repo/tree/main/item/docs/readme.md"},
+	}
+	t.Run("validate file urls", func(t *testing.T) {
+		checkPageUrls(t, urlTests, func(p provider.Provider, webUrl string) (string, error) {
+			return p.FileURL(webUrl, "main", "docs/readme.md")
+		})
+	})
+}
+
+func TestGerritBrowseUrls(t *testing.T) {
+	webUrl := "https://review.example.com/admin/repos/platform/build"
+	cases := []struct {
+		host    *config.ScmHost
+		rel     string
+		wantURL string
+	}{
+		{&config.ScmHost{Provider: config.Gerrit}, "",
+			"https://review.example.com/plugins/gitiles/platform/build/+/refs/heads/main"},
+		{&config.ScmHost{Provider: config.Gerrit, BrowserUrl: "https://android.googlesource.com/"}, "core/Makefile",
+			"https://android.googlesource.com/platform/build/+/refs/heads/main/core/Makefile"},
+		{&config.ScmHost{Provider: config.Gerrit, Browser: config.GerritBrowserGitweb}, "",
+			"https://review.example.com/gitweb?p=platform/build.git;a=shortlog;h=refs/heads/main"},
+		{&config.ScmHost{Provider: config.Gerrit, Browser: config.GerritBrowserGitweb}, "core/Makefile",
+			"https://review.example.com/gitweb?p=platform/build.git;a=blob;f=core/Makefile;hb=refs/heads/main"},
+	}
+	for _, c := range cases {
+		p, err := provider.ForHost(c.host)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, _ := p.RemURL(webUrl, "main")
+		if c.rel != "" {
+			got, _ = p.FileURL(webUrl, "main", c.rel)
+		}
+		if got != c.wantURL {
+			t.Errorf("%s browse url = %s, want %s", c.host.Browser, got, c.wantURL)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("unknown providers should fail", func(t *testing.T) {
+		if _, err := provider.Get("unknown"); err == nil {
+			t.Errorf("expecting an error for an unknown provider")
+		}
+	})
+	t.Run("registered providers should be returned for their hosts", func(t *testing.T) {
+		provider.Register("custom", func(*config.ScmHost) provider.Provider {
+			p, _ := provider.Get(config.GitHub)
+			return p
+		})
+		if _, err := provider.ForHost(&config.ScmHost{Provider: "custom"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("not supported errors should name the provider and the page", func(t *testing.T) {
+		p, _ := provider.Get(config.Gerrit)
+		_, err := p.IssuesURL("https://review.example.com/admin/repos/platform/build")
+		if err == nil || err.Error() != "issues pages are not supported by the gerrit provider" {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+}
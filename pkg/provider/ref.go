@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RefKind is the kind of git reference a browser url points at
+type RefKind string
+
+const (
+	// RefName is a branch or a tag. Tree and blob urls do not tell where the ref name ends
+	// and the file path begins, so the name is resolved against the refs of the repo.
+	RefName        RefKind = "name"
+	RefPullRequest RefKind = "pull-request"
+)
+
+// Ref is the branch, tag or pull request a browser url points at
+type Ref struct {
+	Kind RefKind
+	// Name is the ref name for RefName, possibly followed by a file path
+	Name string
+	// Number is the number of the pull request or merge request
+	Number int
+	// RemoteRef is the ref on the remote holding the head of the pull request, ex: refs/pull/123/head
+	RemoteRef string
+	// LocalBranch is the branch the pull request head is fetched into, ex: pr-123
+	LocalBranch string
+}
+
+// Candidates returns the possible ref names for a RefName, longest first.
+// For "feature/x/docs/README.md" it returns "feature/x/docs/README.md", "feature/x/docs", "feature/x" and "feature".
+func (r *Ref) Candidates() []string {
+	parts := strings.Split(strings.Trim(r.Name, "/"), "/")
+	candidates := make([]string, 0, len(parts))
+	for i := len(parts); i > 0; i-- {
+		candidates = append(candidates, strings.Join(parts[:i], "/"))
+	}
+	return candidates
+}
+
+// refPatterns tells where the ref is in the browser urls of a provider
+type refPatterns struct {
+	// names precede a branch or tag name, ex: /tree/
+	names []string
+	// pull precedes the number of a pull request, ex: /pull/. Providers without pull request refs leave it empty.
+	pull string
+	// remoteRef and localBranch are formats taking the pull request number, ex: refs/pull/%d/head and pr-%d
+	remoteRef   string
+	localBranch string
+}
+
+// parse extracts the ref from the part of a browser url following the hostname, nil when the url points at the repo itself
+func (p refPatterns) parse(path string) (*Ref, error) {
+	for _, pattern := range p.names {
+		if idx := strings.Index(path, pattern); idx != -1 {
+			name := strings.Trim(path[idx+len(pattern):], "/")
+			if name == "" {
+				return nil, nil
+			}
+			return &Ref{Kind: RefName, Name: name}, nil
+		}
+	}
+	if p.pull == "" {
+		return nil, nil
+	}
+	if idx := strings.Index(path, p.pull); idx != -1 {
+		numStr := strings.Split(path[idx+len(p.pull):], "/")[0]
+		num, err := strconv.Atoi(numStr)
+		if err != nil {
+			return nil, errors.Errorf("invalid pull request number %s", numStr)
+		}
+		return &Ref{
+			Kind:        RefPullRequest,
+			Number:      num,
+			RemoteRef:   fmt.Sprintf(p.remoteRef, num),
+			LocalBranch: fmt.Sprintf(p.localBranch, num),
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sourceHut repos belong to ~owner and their issues, patches and builds live on sibling services of git.<domain>
+type sourceHut struct {
+	base
+}
+
+// sourcehut takes patches through mailing lists and has no pull request refs
+var sourceHutRefs = refPatterns{names: []string{"/tree/", "/log/"}}
+
+func (h *sourceHut) ParseBrowserURL(path string) (string, *Ref, error) {
+	repoPath, err := ownerRepoPath(path, h.name)
+	if err != nil {
+		return "", nil, err
+	}
+	ref, err := sourceHutRefs.parse(path)
+	return repoPath, ref, err
+}
+
+// SshCloneURL returns the ssh clone url, without the .git suffix, ex: git@git.sr.ht:~user/repo
+func (h *sourceHut) SshCloneURL(d *CloneURLData) string {
+	return fmt.Sprintf("%s@%s:%s", d.SshUser, d.Hostname, d.RepoPath)
+}
+
+func (h *sourceHut) HttpCloneURL(d *CloneURLData) string {
+	return fmt.Sprintf("%s://%s/%s", d.Scheme, d.Hostname, d.RepoPath)
+}
+
+// serviceUrl returns the url of the repo on another sourcehut service, served from a sibling host of git.<domain>
+// under the same ~owner/name path, ex: https://todo.sr.ht/~user/repo for https://git.sr.ht/~user/repo
+func (h *sourceHut) serviceUrl(webUrl, service string) string {
+	scheme, rest, found := strings.Cut(webUrl, "://")
+	if !found {
+		return webUrl
+	}
+	return scheme + "://" + service + "." + strings.TrimPrefix(rest, "git.")
+}
+
+func (h *sourceHut) RemURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/tree/%s", webUrl, branch), nil
+}
+
+func (h *sourceHut) PrsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/patches", h.serviceUrl(webUrl, "lists")), nil
+}
+
+func (h *sourceHut) BranchesURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/refs", webUrl), nil
+}
+
+func (h *sourceHut) CommitsURL(webUrl, branch string) (string, error) {
+	return fmt.Sprintf("%s/log/%s", webUrl, branch), nil
+}
+
+func (h *sourceHut) TagsURL(webUrl string) (string, error) {
+	return fmt.Sprintf("%s/refs", webUrl), nil
+}
+
+func (h *sourceHut) IssuesURL(webUrl string) (string, error) {
+	return h.serviceUrl(webUrl, "todo"), nil
+}
+
+func (h *sourceHut) PipelinesURL(webUrl string) (string, error) {
+	return h.serviceUrl(webUrl, "builds"), nil
+}
+
+func (h *sourceHut) FileURL(webUrl, ref, rel string) (string, error) {
+	return fmt.Sprintf("%s/tree/%s/item/%s", webUrl, ref, rel), nil
+}
@@ -0,0 +1,132 @@
+package scmapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+// forkPollInterval and forkPollAttempts bound the wait for a newly created fork to become available
+var (
+	forkPollInterval = 2 * time.Second
+	forkPollAttempts = 30
+)
+
+// forkClockSkew is the difference allowed between the clocks of gitr and the scm host when telling new forks from existing ones
+const forkClockSkew = time.Minute
+
+// Fork is a fork of a repository owned by the authenticated user
+type Fork struct {
+	FullPath string
+	// Created is true when the fork was created, and false when an existing fork was found
+	Created bool
+}
+
+// GetOrCreateFork returns the fork of the upstream repo owned by the user the token belongs to,
+// and creates the fork when the user does not have one yet
+func GetOrCreateFork(s *config.ScmHost, upstreamPath, token string) (*Fork, error) {
+	if token == "" {
+		return nil, errors.Errorf("a personal access token for %s is required to fork repos", s.Hostname)
+	}
+	apiUrl, err := GetApiUrl(s)
+	if err != nil {
+		return nil, err
+	}
+	c := newClient(s.Provider, apiUrl, token)
+	switch s.Provider {
+	case config.GitHub:
+		return c.getOrCreateGitHubFork(upstreamPath)
+	case config.GitLab:
+		return c.getOrCreateGitLabFork(upstreamPath)
+	default:
+		return nil, errors.Errorf("provider %s not supported for forking repos", s.Provider)
+	}
+}
+
+type gitHubForkRepo struct {
+	FullName  string    `json:"full_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// getOrCreateGitHubFork asks github to fork the repo. github returns the existing fork of the user when there is one,
+// whatever its name, so a fork created before the request is an existing fork.
+func (c *client) getOrCreateGitHubFork(upstreamPath string) (*Fork, error) {
+	requestedAt := time.Now()
+	var fork gitHubForkRepo
+	if _, err := c.postJSON(fmt.Sprintf("/repos/%s/forks", upstreamPath), &fork); err != nil {
+		return nil, errors.Wrapf(err, "failed to fork %s", upstreamPath)
+	}
+	if fork.CreatedAt.Before(requestedAt.Add(-forkClockSkew)) {
+		return &Fork{FullPath: fork.FullName}, nil
+	}
+	// forks are created asynchronously, so wait until the fork can be fetched
+	if err := c.waitFor(func() (bool, error) {
+		var r gitHubForkRepo
+		status, err := c.getJSON("/repos/"+fork.FullName, nil, &r)
+		if status == http.StatusNotFound {
+			return false, nil
+		}
+		return err == nil, err
+	}); err != nil {
+		return nil, errors.Wrapf(err, "fork %s did not become available", fork.FullName)
+	}
+	return &Fork{FullPath: fork.FullName, Created: true}, nil
+}
+
+type gitLabForkProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	ImportStatus      string `json:"import_status"`
+}
+
+// getOrCreateGitLabFork looks up the forks of the project owned by the user and forks the project when there is none
+func (c *client) getOrCreateGitLabFork(upstreamPath string) (*Fork, error) {
+	projectPath := "/projects/" + url.PathEscape(upstreamPath)
+	q := url.Values{}
+	q.Set("owned", "true")
+	var owned []*gitLabForkProject
+	if _, err := c.getJSON(projectPath+"/forks", q, &owned); err != nil {
+		return nil, errors.Wrapf(err, "failed to look up forks of %s", upstreamPath)
+	}
+	if len(owned) > 0 {
+		return &Fork{FullPath: owned[0].PathWithNamespace}, nil
+	}
+	var created gitLabForkProject
+	if _, err := c.postJSON(projectPath+"/fork", &created); err != nil {
+		return nil, errors.Wrapf(err, "failed to fork %s", upstreamPath)
+	}
+	// the repository of a new fork is imported asynchronously
+	if err := c.waitFor(func() (bool, error) {
+		var p gitLabForkProject
+		if _, err := c.getJSON("/projects/"+url.PathEscape(created.PathWithNamespace), nil, &p); err != nil {
+			return false, err
+		}
+		if p.ImportStatus == "failed" {
+			return false, errors.New("fork import failed")
+		}
+		return p.ImportStatus == "" || p.ImportStatus == "none" || p.ImportStatus == "finished", nil
+	}); err != nil {
+		return nil, errors.Wrapf(err, "fork %s did not become available", created.PathWithNamespace)
+	}
+	return &Fork{FullPath: created.PathWithNamespace, Created: true}, nil
+}
+
+// waitFor polls ready until it returns true, an error or the attempts are exhausted
+func (c *client) waitFor(ready func() (bool, error)) error {
+	for i := 0; i < forkPollAttempts; i++ {
+		ok, err := ready()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		log.Debugf("waiting %s for fork to become available", forkPollInterval)
+		time.Sleep(forkPollInterval)
+	}
+	return errors.New("timed out")
+}
@@ -0,0 +1,125 @@
+package scmapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+func TestGetOrCreateGitHubFork(t *testing.T) {
+	forkPollInterval = time.Millisecond
+	forkLookups := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/kubernetes-sigs/kind/forks", func(w http.ResponseWriter, r *http.Request) {
+		// github returns the existing fork of the user, which was renamed
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(&gitHubForkRepo{FullName: "jane/kind-playground", CreatedAt: time.Now().Add(-24 * time.Hour)})
+	})
+	mux.HandleFunc("/repos/jane/kubectl", func(w http.ResponseWriter, r *http.Request) {
+		// the fork becomes available after it was looked up once
+		forkLookups++
+		if forkLookups < 2 {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&gitHubForkRepo{FullName: "jane/kubectl"})
+	})
+	mux.HandleFunc("/repos/kubernetes/kubectl/forks", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expecting POST but got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(&gitHubForkRepo{FullName: "jane/kubectl", CreatedAt: time.Now()})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	s := &config.ScmHost{Hostname: "github.com", Provider: config.GitHub, Scheme: config.Https, ApiUrl: server.URL}
+
+	t.Run("existing fork should be reused whatever its name", func(t *testing.T) {
+		fork, err := GetOrCreateFork(s, "kubernetes-sigs/kind", "secret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fork.FullPath != "jane/kind-playground" || fork.Created {
+			t.Errorf("expecting existing fork jane/kind-playground but got %+v", fork)
+		}
+	})
+	t.Run("missing fork should be created", func(t *testing.T) {
+		fork, err := GetOrCreateFork(s, "kubernetes/kubectl", "secret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fork.FullPath != "jane/kubectl" || !fork.Created {
+			t.Errorf("expecting created fork jane/kubectl but got %+v", fork)
+		}
+	})
+	t.Run("forking without a token should be an error", func(t *testing.T) {
+		if _, err := GetOrCreateFork(s, "kubernetes/kubectl", ""); err == nil {
+			t.Errorf("expecting an error")
+		}
+	})
+}
+
+func TestGetOrCreateGitLabFork(t *testing.T) {
+	forkPollInterval = time.Millisecond
+	created := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/projects/gitlab-org%2Fci%2Frunner/forks":
+			if r.URL.Query().Get("owned") != "true" {
+				t.Errorf("expecting forks to be filtered by owner but got %s", r.URL)
+			}
+			forks := []*gitLabForkProject{}
+			if created {
+				forks = append(forks, &gitLabForkProject{PathWithNamespace: "jane/ci-runner"})
+			}
+			_ = json.NewEncoder(w).Encode(forks)
+		case "/projects/gitlab-org%2Fci%2Frunner/fork":
+			created = true
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&gitLabForkProject{PathWithNamespace: "jane/runner", ImportStatus: "scheduled"})
+		case "/projects/jane%2Frunner":
+			_ = json.NewEncoder(w).Encode(&gitLabForkProject{PathWithNamespace: "jane/runner", ImportStatus: "finished"})
+		case "/projects/other%2Fdotfiles/forks":
+			_ = json.NewEncoder(w).Encode([]*gitLabForkProject{})
+		case "/projects/other%2Fdotfiles/fork":
+			// the namespace of the user has a project with the same name
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"Project namespace name has already been taken"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	s := &config.ScmHost{Hostname: "gitlab.com", Provider: config.GitLab, Scheme: config.Https, ApiUrl: server.URL}
+
+	t.Run("missing fork should be created", func(t *testing.T) {
+		fork, err := GetOrCreateFork(s, "gitlab-org/ci/runner", "secret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fork.FullPath != "jane/runner" || !fork.Created {
+			t.Errorf("expecting created fork jane/runner but got %+v", fork)
+		}
+	})
+	t.Run("existing fork owned by the user should be reused whatever its name", func(t *testing.T) {
+		fork, err := GetOrCreateFork(s, "gitlab-org/ci/runner", "secret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fork.FullPath != "jane/ci-runner" || fork.Created {
+			t.Errorf("expecting existing fork jane/ci-runner but got %+v", fork)
+		}
+	})
+	t.Run("failing to create the fork should be an error", func(t *testing.T) {
+		if _, err := GetOrCreateFork(s, "other/dotfiles", "secret"); err == nil {
+			t.Errorf("expecting an error")
+		}
+	})
+}
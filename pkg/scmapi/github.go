@@ -0,0 +1,45 @@
+package scmapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+type gitHubRepo struct {
+	FullName string   `json:"full_name"`
+	Archived bool     `json:"archived"`
+	Fork     bool     `json:"fork"`
+	Topics   []string `json:"topics"`
+}
+
+// listGitHubRepos lists the repos of an organization and falls back to the user
+// endpoint when no organization exists with the given name
+func (c *client) listGitHubRepos(owner string) ([]*Repo, error) {
+	repos, status, err := c.listGitHubReposAt(fmt.Sprintf("/orgs/%s/repos", owner))
+	if status == http.StatusNotFound {
+		repos, _, err = c.listGitHubReposAt(fmt.Sprintf("/users/%s/repos", owner))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list repos of %s", owner)
+	}
+	return repos, nil
+}
+
+func (c *client) listGitHubReposAt(path string) ([]*Repo, int, error) {
+	repos := make([]*Repo, 0)
+	for page := 1; ; page++ {
+		var pageRepos []*gitHubRepo
+		status, err := c.getJSON(path, pageQuery(page), &pageRepos)
+		if err != nil {
+			return nil, status, err
+		}
+		for _, r := range pageRepos {
+			repos = append(repos, &Repo{FullPath: r.FullName, Archived: r.Archived, Fork: r.Fork, Topics: r.Topics})
+		}
+		if len(pageRepos) < pageSize {
+			return repos, status, nil
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package scmapi
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+type gitLabProject struct {
+	PathWithNamespace string      `json:"path_with_namespace"`
+	Archived          bool        `json:"archived"`
+	ForkedFromProject interface{} `json:"forked_from_project"`
+	Topics            []string    `json:"topics"`
+	TagList           []string    `json:"tag_list"`
+}
+
+type gitLabGroup struct {
+	FullPath string `json:"full_path"`
+}
+
+// listGitLabRepos lists the projects of a group and recursively the projects of all its subgroups.
+// Projects shared with the group from other namespaces are left out.
+func (c *client) listGitLabRepos(group string) ([]*Repo, error) {
+	repos := make([]*Repo, 0)
+	for page := 1; ; page++ {
+		var projects []*gitLabProject
+		q := pageQuery(page)
+		q.Set("with_shared", "false")
+		if _, err := c.getJSON(fmt.Sprintf("/groups/%s/projects", url.PathEscape(group)), q, &projects); err != nil {
+			return nil, errors.Wrapf(err, "failed to list projects of %s group", group)
+		}
+		for _, p := range projects {
+			topics := p.Topics
+			if len(topics) == 0 {
+				topics = p.TagList
+			}
+			repos = append(repos, &Repo{FullPath: p.PathWithNamespace, Archived: p.Archived, Fork: p.ForkedFromProject != nil, Topics: topics})
+		}
+		if len(projects) < pageSize {
+			break
+		}
+	}
+	for page := 1; ; page++ {
+		var subgroups []*gitLabGroup
+		if _, err := c.getJSON(fmt.Sprintf("/groups/%s/subgroups", url.PathEscape(group)), pageQuery(page), &subgroups); err != nil {
+			return nil, errors.Wrapf(err, "failed to list subgroups of %s group", group)
+		}
+		for _, sg := range subgroups {
+			subgroupRepos, err := c.listGitLabRepos(sg.FullPath)
+			if err != nil {
+				return nil, err
+			}
+			repos = append(repos, subgroupRepos...)
+		}
+		if len(subgroups) < pageSize {
+			break
+		}
+	}
+	return repos, nil
+}
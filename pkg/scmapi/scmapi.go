@@ -0,0 +1,169 @@
+package scmapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+// pageSize is the number of items requested per page from the provider api
+const pageSize = 100
+
+// Repo is a repository listed through the api of an scm provider
+type Repo struct {
+	// FullPath is the path of the repo on the scm host, ex: owner/repo or group/subgroup/project
+	FullPath string
+	Archived bool
+	Fork     bool
+	Topics   []string
+}
+
+// Filter selects which of the listed repos are cloned
+type Filter struct {
+	IncludeArchived bool
+	IncludeForks    bool
+	NameRegex       *regexp.Regexp
+	Topic           string
+}
+
+// Match returns true if the repo passes all the criteria of the filter
+func (f *Filter) Match(r *Repo) bool {
+	if r.Archived && !f.IncludeArchived {
+		return false
+	}
+	if r.Fork && !f.IncludeForks {
+		return false
+	}
+	if f.NameRegex != nil && !f.NameRegex.MatchString(r.FullPath) {
+		return false
+	}
+	if f.Topic != "" {
+		for _, t := range r.Topics {
+			if strings.EqualFold(t, f.Topic) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// GetApiUrl returns the base url of the provider api for the scm host.
+// The apiUrl configured for the host takes precedence over the provider defaults.
+func GetApiUrl(s *config.ScmHost) (string, error) {
+	if s.ApiUrl != "" {
+		return strings.TrimSuffix(s.ApiUrl, "/"), nil
+	}
+	scheme := s.Scheme
+	if scheme == "" {
+		scheme = config.Https
+	}
+	switch s.Provider {
+	case config.GitHub:
+		if s.Hostname == "github.com" {
+			return "https://api.github.com", nil
+		}
+		return fmt.Sprintf("%s://%s/api/v3", scheme, s.Hostname), nil
+	case config.GitLab:
+		return fmt.Sprintf("%s://%s/api/v4", scheme, s.Hostname), nil
+	default:
+		return "", errors.Errorf("provider %s not supported for api access", s.Provider)
+	}
+}
+
+// ListRepos returns all the repos of a github org/user or a gitlab group including its subgroups
+func ListRepos(s *config.ScmHost, owner, token string) ([]*Repo, error) {
+	apiUrl, err := GetApiUrl(s)
+	if err != nil {
+		return nil, err
+	}
+	c := newClient(s.Provider, apiUrl, token)
+	switch s.Provider {
+	case config.GitHub:
+		return c.listGitHubRepos(owner)
+	case config.GitLab:
+		return c.listGitLabRepos(owner)
+	default:
+		return nil, errors.Errorf("provider %s not supported for listing repos", s.Provider)
+	}
+}
+
+type client struct {
+	provider   config.ScmProvider
+	apiUrl     string
+	token      string
+	httpClient *http.Client
+}
+
+func newClient(provider config.ScmProvider, apiUrl, token string) *client {
+	return &client{
+		provider:   provider,
+		apiUrl:     apiUrl,
+		token:      strings.TrimSpace(token),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// getJSON sends a GET request to the api and decodes the json response into v.
+// The http status code is returned so callers can react to 404 responses.
+func (c *client) getJSON(path string, query url.Values, v interface{}) (int, error) {
+	return c.do(http.MethodGet, path, query, v)
+}
+
+// postJSON sends a POST request without a body to the api and decodes the json response into v
+func (c *client) postJSON(path string, v interface{}) (int, error) {
+	return c.do(http.MethodPost, path, nil, v)
+}
+
+func (c *client) do(method, path string, query url.Values, v interface{}) (int, error) {
+	reqUrl := c.apiUrl + path
+	if len(query) > 0 {
+		reqUrl = reqUrl + "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, reqUrl, nil)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create request for %s", reqUrl)
+	}
+	c.setHeaders(req)
+	log.Debugf("%s %s", method, reqUrl)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to send request to %s", reqUrl)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.Errorf("%s returned %s", reqUrl, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp.StatusCode, errors.Wrapf(err, "failed to decode response from %s", reqUrl)
+	}
+	return resp.StatusCode, nil
+}
+
+func (c *client) setHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	if c.token == "" {
+		return
+	}
+	switch c.provider {
+	case config.GitLab:
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
+func pageQuery(page int) url.Values {
+	q := url.Values{}
+	q.Set("per_page", fmt.Sprintf("%d", pageSize))
+	q.Set("page", fmt.Sprintf("%d", page))
+	return q
+}
@@ -0,0 +1,158 @@
+package scmapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+func TestGetApiUrl(t *testing.T) {
+	var tests = []struct {
+		host     *config.ScmHost
+		expected string
+	}{
+		{&config.ScmHost{Hostname: "github.com", Provider: config.GitHub, Scheme: config.Https}, "https://api.github.com"},
+		{&config.ScmHost{Hostname: "github.example.com", Provider: config.GitHub, Scheme: config.Https}, "https://github.example.com/api/v3"},
+		{&config.ScmHost{Hostname: "gitlab.example.com", Provider: config.GitLab, Scheme: config.Http}, "http://gitlab.example.com/api/v4"},
+		{&config.ScmHost{Hostname: "gitlab.example.com", Provider: config.GitLab}, "https://gitlab.example.com/api/v4"},
+		{&config.ScmHost{Hostname: "gitlab.com", Provider: config.GitLab, Scheme: config.Https, ApiUrl: "http://127.0.0.1:8080/api/v4/"}, "http://127.0.0.1:8080/api/v4"},
+	}
+	t.Run("api url should be derived from the host unless configured", func(t *testing.T) {
+		for _, tc := range tests {
+			apiUrl, err := GetApiUrl(tc.host)
+			if err != nil {
+				t.Errorf("unexpected error for %s: %v", tc.host.Hostname, err)
+				continue
+			}
+			if apiUrl != tc.expected {
+				t.Errorf("expecting %s but got %s", tc.expected, apiUrl)
+			}
+		}
+	})
+}
+
+func TestListGitHubRepos(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expecting bearer token but got %q", r.Header.Get("Authorization"))
+		}
+		page := r.URL.Query().Get("page")
+		repos := make([]*gitHubRepo, 0)
+		switch page {
+		case "1":
+			for i := 0; i < pageSize; i++ {
+				repos = append(repos, &gitHubRepo{FullName: fmt.Sprintf("acme/repo-%d", i)})
+			}
+		case "2":
+			repos = append(repos, &gitHubRepo{FullName: "acme/last", Archived: true, Topics: []string{"backend"}})
+		}
+		_ = json.NewEncoder(w).Encode(repos)
+	})
+	mux.HandleFunc("/users/jane/repos", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*gitHubRepo{{FullName: "jane/dotfiles", Fork: true}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	s := &config.ScmHost{Hostname: "github.com", Provider: config.GitHub, Scheme: config.Https, ApiUrl: server.URL}
+
+	t.Run("all pages of an org should be listed", func(t *testing.T) {
+		repos, err := ListRepos(s, "acme", "secret\n")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repos) != pageSize+1 {
+			t.Fatalf("expecting %d repos but got %d", pageSize+1, len(repos))
+		}
+		if last := repos[pageSize]; last.FullPath != "acme/last" || !last.Archived {
+			t.Errorf("expecting archived acme/last but got %+v", last)
+		}
+	})
+	t.Run("user repos should be listed when the org does not exist", func(t *testing.T) {
+		repos, err := ListRepos(s, "jane", "secret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repos) != 1 || repos[0].FullPath != "jane/dotfiles" || !repos[0].Fork {
+			t.Errorf("expecting forked jane/dotfiles but got %+v", repos)
+		}
+	})
+}
+
+func TestListGitLabRepos(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/groups/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			t.Errorf("expecting private token header but got %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		if strings.HasSuffix(r.URL.Path, "/projects") && r.URL.Query().Get("with_shared") != "false" {
+			t.Errorf("expecting shared projects to be excluded from %s", r.URL)
+		}
+		switch r.URL.EscapedPath() {
+		case "/groups/acme/projects":
+			_ = json.NewEncoder(w).Encode([]*gitLabProject{{PathWithNamespace: "acme/api", TagList: []string{"backend"}}})
+		case "/groups/acme/subgroups":
+			_ = json.NewEncoder(w).Encode([]*gitLabGroup{{FullPath: "acme/platform"}})
+		case "/groups/acme%2Fplatform/projects":
+			_ = json.NewEncoder(w).Encode([]*gitLabProject{
+				{PathWithNamespace: "acme/platform/infra", Topics: []string{"ops"}},
+				{PathWithNamespace: "acme/platform/old", Archived: true},
+				{PathWithNamespace: "acme/platform/fork", ForkedFromProject: map[string]interface{}{"id": 1}},
+			})
+		case "/groups/acme%2Fplatform/subgroups":
+			_ = json.NewEncoder(w).Encode([]*gitLabGroup{})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	s := &config.ScmHost{Hostname: "gitlab.example.com", Provider: config.GitLab, Scheme: config.Https, ApiUrl: server.URL}
+
+	repos, err := ListRepos(s, "acme", "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Run("projects of subgroups should be listed", func(t *testing.T) {
+		expected := []string{"acme/api", "acme/platform/infra", "acme/platform/old", "acme/platform/fork"}
+		if len(repos) != len(expected) {
+			t.Fatalf("expecting %d repos but got %d", len(expected), len(repos))
+		}
+		for i := range expected {
+			if repos[i].FullPath != expected[i] {
+				t.Errorf("expecting %s but got %s", expected[i], repos[i].FullPath)
+			}
+		}
+		if !repos[3].Fork {
+			t.Errorf("expecting %s to be a fork", repos[3].FullPath)
+		}
+	})
+	t.Run("filters should exclude archived repos, forks and non matching repos", func(t *testing.T) {
+		var filterTests = []struct {
+			filter   *Filter
+			expected []string
+		}{
+			{&Filter{}, []string{"acme/api", "acme/platform/infra"}},
+			{&Filter{IncludeArchived: true, IncludeForks: true}, []string{"acme/api", "acme/platform/infra", "acme/platform/old", "acme/platform/fork"}},
+			{&Filter{NameRegex: regexp.MustCompile(`platform/`)}, []string{"acme/platform/infra"}},
+			{&Filter{Topic: "backend"}, []string{"acme/api"}},
+		}
+		for _, ft := range filterTests {
+			matched := make([]string, 0)
+			for _, r := range repos {
+				if ft.filter.Match(r) {
+					matched = append(matched, r.FullPath)
+				}
+			}
+			if fmt.Sprint(matched) != fmt.Sprint(ft.expected) {
+				t.Errorf("expecting %v but got %v", ft.expected, matched)
+			}
+		}
+	})
+}
@@ -1,9 +1,23 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/swarupdonepudi/gitr/pkg/clonerr"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
+)
+
+// Exit codes of clone failures, so that scripts can tell the causes apart
+const (
+	ExitRepoNotFound    = 10
+	ExitAuthFailed      = 11
+	ExitHostUnreachable = 12
+	ExitHostKeyMismatch = 13
+	ExitDiskFull        = 14
 )
 
 // Predefined error messages with helpful context
@@ -28,6 +42,14 @@ func ConfigNotFound() {
 
 // ConfigError displays a config-related error
 func ConfigError(err error) {
+	var invalid *config.InvalidConfigErr
+	if errors.As(err, &invalid) {
+		Error(
+			"Invalid Configuration",
+			invalid.Error(),
+			"Fix the problems in "+Path(invalid.Path)+" and run "+Cmd("gitr config validate")+" to check it again",
+		)
+	}
 	Error(
 		"Configuration Error",
 		fmt.Sprintf("Failed to load gitr configuration: %v", err),
@@ -35,12 +57,21 @@ func ConfigError(err error) {
 	)
 }
 
+// ConfigWarnings displays the problems of a config file that do not stop gitr from loading it
+func ConfigWarnings(path string, problems []*config.Problem) {
+	lines := make([]string, 0, len(problems))
+	for _, p := range problems {
+		lines = append(lines, p.String())
+	}
+	Warn(fmt.Sprintf("%d Warning(s) in %s", len(problems), path), strings.Join(lines, "\n"))
+}
+
 // UnknownSCMHost displays an error for unrecognized SCM hosts
 func UnknownSCMHost(hostname string) {
 	Error(
 		"Unknown SCM Host",
 		fmt.Sprintf("The hostname %s is not configured in gitr.", Path(hostname)),
-		"Add it to your config with "+Cmd("gitr config edit"),
+		"Add it to your config with "+Cmd("gitr config host add "+hostname+" --provider <provider>"),
 	)
 }
 
@@ -54,6 +85,24 @@ func CloneURLRequired() {
 	)
 }
 
+// CloneURLListEmpty displays an error when a bulk clone list has no urls
+func CloneURLListEmpty(source string) {
+	Error(
+		"No Repositories to Clone",
+		fmt.Sprintf("No repository URLs were found in %s.", Path(source)),
+		"Provide one URL per line; blank lines and lines starting with # are ignored",
+	)
+}
+
+// ForkWithUrlList displays an error when --fork is combined with a bulk clone list
+func ForkWithUrlList() {
+	Error(
+		"Fork Not Supported for Lists",
+		"The --fork flag clones a single repository and can not be combined with --file.",
+		"Fork the repositories one at a time with gitr clone --fork <url>",
+	)
+}
+
 // NoRemotesFound displays an error when git repo has no remotes
 func NoRemotesFound() {
 	Error(
@@ -72,26 +121,81 @@ func FailedToGetBranch(err error) {
 	)
 }
 
-// FailedToClone displays an error when cloning fails
+// FailedToClone displays an error when cloning fails, with a hint and exit code specific to the cause
 func FailedToClone(err error) {
-	// Check if this is a "repo not found" error and show a clearer message
-	errStr := err.Error()
-	if strings.Contains(strings.ToLower(errStr), "not found") ||
-		strings.Contains(strings.ToLower(errStr), "does not exist") {
+	switch {
+	case errors.Is(err, clonerr.ErrRepoNotFound):
 		RepoNotFound()
-		return
+	case errors.Is(err, clonerr.ErrAuthFailed):
+		ErrorWithCode(ExitAuthFailed,
+			"Authentication Failed",
+			fmt.Sprintf("The host rejected the credentials used to clone: %v", err),
+			"For HTTPS, pass "+Cmd("--token")+" or set "+Cmd("GITR_TOKEN_<HOST>")+" with a token that can read the repository",
+			"For SSH, check that your key is loaded with "+Cmd("ssh-add -l")+" and added to your account",
+		)
+	case errors.Is(err, clonerr.ErrHostUnreachable):
+		ErrorWithCode(ExitHostUnreachable,
+			"Host Unreachable",
+			fmt.Sprintf("Could not connect to the git host: %v", err),
+			"Check your network connection, VPN and proxy settings",
+			"Verify the hostname in the URL is spelled correctly",
+		)
+	case errors.Is(err, clonerr.ErrHostKeyMismatch):
+		ErrorWithCode(ExitHostKeyMismatch,
+			"SSH Host Key Mismatch",
+			fmt.Sprintf("The SSH host key of the git host does not match your known_hosts file: %v", err),
+			"Verify the new host key fingerprint with your git host before trusting it",
+			"Remove the old key with "+Cmd("ssh-keygen -R <host>")+" once verified",
+		)
+	case errors.Is(err, clonerr.ErrDiskFull):
+		ErrorWithCode(ExitDiskFull,
+			"Disk Full",
+			fmt.Sprintf("There is not enough space left to clone the repository: %v", err),
+			"Free up disk space, or use "+Cmd("--depth=1")+" or "+Cmd("--filter=blob:none")+" for a smaller clone",
+		)
+	default:
+		Error(
+			"Clone Failed",
+			fmt.Sprintf("Failed to clone the repository: %v", err),
+			"Check your network connection and repository URL",
+			"For private repos, ensure you have the correct access token",
+		)
 	}
+}
+
+// BulkCloneFailed displays an error when one or more repositories of a bulk clone failed
+func BulkCloneFailed(failed, total int) {
+	Error(
+		"Bulk Clone Failed",
+		fmt.Sprintf("%d of %d repositories failed to clone.", failed, total),
+		"Re-run with "+Cmd("--debug")+" for more details",
+		"Repositories that were cloned successfully are skipped on the next run",
+	)
+}
+
+// DirtyWorktree displays an error when an existing clone can not be switched because of local changes
+func DirtyWorktree(repoPath string) {
+	Error(
+		"Uncommitted Changes",
+		fmt.Sprintf("The repository at %s has uncommitted changes.", Path(repoPath)),
+		"Commit or stash your changes, then run the command again",
+	)
+}
+
+// ExistingDirNotRepo displays an error when the clone path is taken by a directory that is not a git repo
+func ExistingDirNotRepo(clonePath string) {
 	Error(
-		"Clone Failed",
-		fmt.Sprintf("Failed to clone the repository: %v", err),
-		"Check your network connection and repository URL",
-		"For private repos, ensure you have the correct access token",
+		"Clone Path Not Empty",
+		fmt.Sprintf("%s already exists and is not a git repository.", Path(clonePath)),
+		"Move or remove the directory, then run the command again",
+		"Use "+Cmd("--on-existing-dir=backup")+" to move it aside or "+Cmd("--on-existing-dir=overwrite")+" to replace it",
+		"Set "+Cmd("onExistingDir")+" in the clone config of the host to change the default",
 	)
 }
 
 // RepoNotFound displays an error when a repository doesn't exist
 func RepoNotFound() {
-	Error(
+	ErrorWithCode(ExitRepoNotFound,
 		"Repository Not Found",
 		"The repository does not exist or you don't have access to it.",
 		"Verify the repository URL is correct",
@@ -109,6 +213,19 @@ func ClipboardError(err error) {
 	)
 }
 
+// FailedToOpenPage displays an error when the web page of a gitr command can not be opened,
+// calling out pages the scm provider does not have
+func FailedToOpenPage(err error) {
+	var notSupported *provider.NotSupportedErr
+	if errors.As(err, &notSupported) {
+		Error(
+			"Page Not Available",
+			fmt.Sprintf("%s repos do not have a %s page.", notSupported.Provider, notSupported.Page),
+		)
+	}
+	GenericError("Failed to Open Page", "Could not build the url of the web page", err)
+}
+
 // FlagParseError displays an error for flag parsing issues
 func FlagParseError(flag string, err error) {
 	Error(
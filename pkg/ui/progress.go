@@ -24,6 +24,7 @@ const (
 	PhaseCompressing
 	PhaseReceiving
 	PhaseResolving
+	PhaseLfs
 	PhaseDone
 )
 
@@ -132,6 +133,7 @@ func (m ProgressModel) View() string {
 		{PhaseCompressing, "Compressing objects", "⚡"},
 		{PhaseReceiving, "Receiving objects", "↓"},
 		{PhaseResolving, "Resolving deltas", "🔗"},
+		{PhaseLfs, "Downloading LFS objects", "📦"},
 	}
 
 	for _, p := range phases {
@@ -216,6 +218,7 @@ var (
 	compressingRe = regexp.MustCompile(`Compressing objects:\s*(\d+)%\s*\((\d+)/(\d+)\)`)
 	receivingRe   = regexp.MustCompile(`Receiving objects:\s*(\d+)%\s*\((\d+)/(\d+)\)(?:,\s*([0-9.]+\s*[KMG]iB))?(?:\s*\|\s*([0-9.]+\s*[KMG]iB/s))?`)
 	resolvingRe   = regexp.MustCompile(`Resolving deltas:\s*(\d+)%\s*\((\d+)/(\d+)\)`)
+	lfsRe         = regexp.MustCompile(`Downloading LFS objects:\s*(\d+)%\s*\((\d+)/(\d+)\)(?:,\s*[0-9.]+\s*[KMG]?i?B)?(?:\s*\|\s*([0-9.]+\s*[KMG]?i?B/s))?`)
 	doneRe        = regexp.MustCompile(`done\.?\s*$`)
 )
 
@@ -232,7 +235,12 @@ func (p *GitProgressParser) ParseLine(line string) {
 	}
 
 	// Parse different progress patterns
-	if matches := receivingRe.FindStringSubmatch(line); matches != nil {
+	if matches := lfsRe.FindStringSubmatch(line); matches != nil {
+		percentage := parseInt(matches[1])
+		current := parseInt(matches[2])
+		total := parseInt(matches[3])
+		p.progress.Update(PhaseLfs, percentage, current, total, matches[4], "")
+	} else if matches := receivingRe.FindStringSubmatch(line); matches != nil {
 		percentage := parseInt(matches[1])
 		current := parseInt(matches[2])
 		total := parseInt(matches[3])
@@ -339,11 +347,14 @@ type CloneProgressDisplay struct {
 	writer   *ProgressWriter
 }
 
-// NewCloneProgressDisplay creates a new progress display
+// NewCloneProgressDisplay creates a new progress display.
+// In quiet mode the display only tracks progress and never renders to the terminal.
 func NewCloneProgressDisplay() *CloneProgressDisplay {
 	progress := NewProgressInfo()
-	model := NewProgressModel(progress)
-	program := tea.NewProgram(model)
+	var program *tea.Program
+	if !quiet {
+		program = tea.NewProgram(NewProgressModel(progress))
+	}
 	parser := NewGitProgressParser(progress, program)
 	writer := NewProgressWriter(parser)
 
@@ -357,6 +368,9 @@ func NewCloneProgressDisplay() *CloneProgressDisplay {
 
 // Start starts the progress display
 func (d *CloneProgressDisplay) Start() {
+	if d.program == nil {
+		return
+	}
 	go func() {
 		d.program.Run()
 	}()
@@ -371,6 +385,9 @@ func (d *CloneProgressDisplay) Writer() io.Writer {
 // Stop stops the progress display
 func (d *CloneProgressDisplay) Stop() {
 	d.progress.Update(PhaseDone, 100, 0, 0, "", "")
+	if d.program == nil {
+		return
+	}
 	time.Sleep(100 * time.Millisecond) // Let UI update
 	d.program.Send(doneMsg{})
 	time.Sleep(50 * time.Millisecond) // Give time to clean up
@@ -0,0 +1,29 @@
+package ui
+
+import "testing"
+
+func TestGitProgressParser(t *testing.T) {
+	var tests = []struct {
+		line       string
+		phase      ProgressPhase
+		percentage int
+		current    int
+		total      int
+		speed      string
+	}{
+		{"Receiving objects:  45% (450/1000), 1.20 MiB | 2.00 MiB/s", PhaseReceiving, 45, 450, 1000, "1.20 MiB"},
+		{"Resolving deltas: 100% (20/20)", PhaseResolving, 100, 20, 20, ""},
+		{"Downloading LFS objects:  50% (1/2), 3.4 MB | 1.2 MB/s", PhaseLfs, 50, 1, 2, "1.2 MB/s"},
+		{"Downloading LFS objects:  25% (1/4)", PhaseLfs, 25, 1, 4, ""},
+	}
+	t.Run("progress lines should update the phase", func(t *testing.T) {
+		for _, tc := range tests {
+			progress := NewProgressInfo()
+			NewGitProgressParser(progress, nil).ParseLine(tc.line)
+			phase, percentage, current, total, speed, _ := progress.GetSnapshot()
+			if phase != tc.phase || percentage != tc.percentage || current != tc.current || total != tc.total || speed != tc.speed {
+				t.Errorf("unexpected progress %v %d %d/%d %q for %q", phase, percentage, current, total, speed, tc.line)
+			}
+		}
+	})
+}
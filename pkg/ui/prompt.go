@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-isatty"
+)
+
+// promptLock serializes prompts of concurrent clones so that answers are read for the right question
+var promptLock sync.Mutex
+
+// Confirm asks a yes/no question on the terminal and returns true only when the answer is yes.
+// The answer is always no when stdin is not a terminal.
+func Confirm(question string) bool {
+	promptLock.Lock()
+	defer promptLock.Unlock()
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return false
+	}
+	fmt.Printf("%s  %s %s ", warningIcon.Render(iconWarning), warningTitle.Render(question), Dim("[y/N]"))
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -41,6 +41,9 @@ func CloneSuccess(clonePath string, clipboardEnabled bool) {
 
 // RepoAlreadyExists displays a message when the repo already exists
 func RepoAlreadyExists(clonePath string) {
+	if quiet {
+		return
+	}
 	displayPath := clonePath
 	if home, err := os.UserHomeDir(); err == nil {
 		if strings.HasPrefix(clonePath, home) {
@@ -53,8 +56,13 @@ func RepoAlreadyExists(clonePath string) {
 }
 
 // ConfigInitSuccess displays a success message after config init
-func ConfigInitSuccess() {
-	configPath := "~/.gitr.yaml"
+func ConfigInitSuccess(path string) {
+	configPath := path
+	if home, err := os.UserHomeDir(); err == nil {
+		if strings.HasPrefix(path, home) {
+			configPath = "~" + strings.TrimPrefix(path, home)
+		}
+	}
 	Success(
 		"Configuration initialized",
 		fmt.Sprintf("Config file created at %s", Path(configPath)),
@@ -130,10 +138,70 @@ func ClonePath(path string, clipboardEnabled bool) {
 	}
 }
 
+// ForkReady displays a message once the fork of a repository is available
+func ForkReady(forkPath, upstreamPath string, created bool) {
+	action := "Using existing fork"
+	if created {
+		action = "Created fork"
+	}
+	fmt.Println()
+	Info(fmt.Sprintf("%s %s of %s", action, Path(forkPath), Path(upstreamPath)))
+}
+
+// CheckedOutRef displays a message after switching a clone to the ref of a browser url
+func CheckedOutRef(ref string) {
+	if quiet {
+		return
+	}
+	Info(fmt.Sprintf("Checked out %s", Path(ref)))
+}
+
+// BulkCloning displays a message when starting to clone many repositories
+func BulkCloning(count, jobs int) {
+	fmt.Printf("\n%s  %s\n",
+		infoIcon.Render("↓"),
+		Dim(fmt.Sprintf("Cloning %d repositories with %d parallel jobs...", count, jobs)))
+}
+
+// BulkCloneSuccess displays a success message after a bulk clone
+func BulkCloneSuccess(cloned, alreadyPresent int) {
+	Success(
+		"Bulk clone complete",
+		fmt.Sprintf("%d cloned, %d already present", cloned, alreadyPresent),
+	)
+}
+
 // Cloning displays a message when starting to clone a repository
 func Cloning(repoUrl string) {
+	if quiet {
+		return
+	}
 	fmt.Printf("\n%s  %s %s\n",
 		infoIcon.Render("↓"),
 		Dim("Cloning"),
 		Path(repoUrl))
 }
+
+// ExistingDirBackedUp displays a message when an existing directory at the clone path was moved aside
+func ExistingDirBackedUp(clonePath, backupPath string) {
+	Info(fmt.Sprintf("Moved existing %s to %s", Path(clonePath), Path(backupPath)))
+}
+
+// UpdatingSubmodules displays a message when starting to clone the submodules of a repository
+func UpdatingSubmodules(repoPath string) {
+	if quiet {
+		return
+	}
+	fmt.Printf("\n%s  %s %s\n",
+		infoIcon.Render("↓"),
+		Dim("Updating submodules of"),
+		Path(repoPath))
+}
+
+// LinkedSubmodule displays a message when a submodule was checked out using its gitr clone as alternate
+func LinkedSubmodule(submodulePath, clonePath string) {
+	if quiet {
+		return
+	}
+	Info(fmt.Sprintf("Linked submodule %s to %s", Path(submodulePath), Path(clonePath)))
+}
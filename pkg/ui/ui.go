@@ -2,16 +2,35 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/swarupdonepudi/gitr/pkg/redact"
 )
 
+// errOut is where errors are printed, replaced in tests to capture the output
+var errOut io.Writer = os.Stderr
+
+// quiet suppresses per-repository clone output such as progress bars,
+// used when many repositories are cloned concurrently
+var quiet bool
+
+// SetQuiet enables or disables per-repository clone output
+func SetQuiet(q bool) {
+	quiet = q
+}
+
 // Error prints a styled error message and exits with code 1
 func Error(title, message string, hints ...string) {
+	ErrorWithCode(1, title, message, hints...)
+}
+
+// ErrorWithCode prints a styled error message and exits with the code
+func ErrorWithCode(code int, title, message string, hints ...string) {
 	printError(title, message, hints...)
-	os.Exit(1)
+	os.Exit(code)
 }
 
 // ErrorWithoutExit prints a styled error message without exiting
@@ -20,32 +39,35 @@ func ErrorWithoutExit(title, message string, hints ...string) {
 }
 
 func printError(title, message string, hints ...string) {
-	fmt.Fprintln(os.Stderr)
+	// errors often wrap urls, headers and tokens, so everything is redacted before it is printed
+	title = redact.String(title)
+	message = redact.String(message)
+	fmt.Fprintln(errOut)
 
 	// Icon and title
-	fmt.Fprintf(os.Stderr, "%s  %s\n",
+	fmt.Fprintf(errOut, "%s  %s\n",
 		errorIcon.Render(iconError),
 		errorTitle.Render(title))
 
 	// Message
 	if message != "" {
-		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(errOut)
 		for _, line := range strings.Split(message, "\n") {
-			fmt.Fprintf(os.Stderr, "   %s\n", errorMessage.Render(line))
+			fmt.Fprintf(errOut, "   %s\n", errorMessage.Render(line))
 		}
 	}
 
 	// Hints
 	if len(hints) > 0 {
-		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(errOut)
 		for _, hint := range hints {
-			fmt.Fprintf(os.Stderr, "   %s %s\n",
+			fmt.Fprintf(errOut, "   %s %s\n",
 				dimStyle.Render("Hint:"),
-				hintStyle.Render(hint))
+				hintStyle.Render(redact.String(hint)))
 		}
 	}
 
-	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(errOut)
 }
 
 // Success prints a styled success message
@@ -72,6 +94,7 @@ func Success(title string, details ...string) {
 
 // Warn prints a styled warning message
 func Warn(title, message string) {
+	message = redact.String(message)
 	fmt.Println()
 
 	// Icon and title
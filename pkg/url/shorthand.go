@@ -0,0 +1,64 @@
+package url
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/swarupdonepudi/gitr/pkg/config"
+)
+
+// IsShorthand returns true for repo references that are neither urls nor local paths, ex: owner/repo, gh:owner/repo
+func IsShorthand(ref string) bool {
+	if ref == "" || strings.Contains(ref, "://") || strings.Contains(ref, "@") {
+		return false
+	}
+	if strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, ".") || strings.HasPrefix(ref, "~") {
+		return false
+	}
+	return strings.Contains(ref, "/")
+}
+
+// ExpandShorthand returns the browser url of a shorthand repo reference and returns any other input unchanged.
+//
+//	owner/repo             -> https://<defaultHost>/owner/repo
+//	gh:owner/repo          -> https://github.com/owner/repo
+//	github.com/owner/repo  -> https://github.com/owner/repo
+func ExpandShorthand(cfg *config.GitrConfig, ref string) (string, error) {
+	if !IsShorthand(ref) {
+		return ref, nil
+	}
+	hostname, repoPath := "", ref
+	if alias, rest, found := strings.Cut(ref, ":"); found {
+		aliasHostname, ok := config.GetAliasHostname(cfg, alias)
+		if !ok {
+			return "", errors.Errorf("unknown host alias %s in %s", alias, ref)
+		}
+		hostname, repoPath = aliasHostname, rest
+	} else if first, rest, _ := strings.Cut(ref, "/"); isConfiguredHost(cfg, first) {
+		hostname, repoPath = first, rest
+	} else {
+		if cfg.DefaultHost == "" {
+			return "", errors.Errorf("no defaultHost configured to resolve %s", ref)
+		}
+		hostname = cfg.DefaultHost
+	}
+	repoPath = strings.Trim(repoPath, "/")
+	if !strings.Contains(repoPath, "/") {
+		return "", errors.Errorf("%s is not a repo path, expecting owner/repo", repoPath)
+	}
+	s, err := config.GetScmHost(cfg, hostname)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve %s", ref)
+	}
+	scheme := s.Scheme
+	if scheme == "" {
+		scheme = config.Https
+	}
+	return fmt.Sprintf("%s://%s/%s", scheme, s.Hostname, repoPath), nil
+}
+
+func isConfiguredHost(cfg *config.GitrConfig, hostname string) bool {
+	_, err := config.GetScmHost(cfg, hostname)
+	return err == nil
+}
@@ -5,18 +5,18 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/skratchdot/open-golang/open"
 	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
 )
 
 // StripQueryParams removes query parameters and fragments from URLs.
 // This handles URLs copied from browsers that include tracking params like ?utm_source=...
 func StripQueryParams(inputUrl string) string {
-	// Handle SSH URLs (git@host:path) - they don't have query params
-	if strings.HasPrefix(inputUrl, "git@") && !strings.Contains(inputUrl, "://") {
-		// For SSH format git@host:path, query params would be after the path
+	// Handle SSH URLs (user@host:path) - they don't have query params
+	if isScpLikeUrl(inputUrl) {
+		// For SSH format user@host:path, query params would be after the path
 		if idx := strings.Index(inputUrl, "?"); idx != -1 {
 			return inputUrl[:idx]
 		}
@@ -49,8 +49,15 @@ func IsGitUrl(repoUrl string) bool {
 	return strings.HasSuffix(repoUrl, ".git")
 }
 
+// scpLikeUrl matches the scp-like syntax of ssh urls for any user, ex: deploy@git.example.com:owner/repo.git
+var scpLikeUrl = regexp.MustCompile(`^[^/@:]+@[^/:]+:`)
+
 func IsGitSshUrl(repoUrl string) bool {
-	return strings.HasPrefix(repoUrl, "ssh://") || strings.HasPrefix(repoUrl, "git@")
+	return strings.HasPrefix(repoUrl, "ssh://") || isScpLikeUrl(repoUrl)
+}
+
+func isScpLikeUrl(repoUrl string) bool {
+	return !strings.Contains(repoUrl, "://") && scpLikeUrl.MatchString(repoUrl)
 }
 
 func IsGitHttpUrlHasUsername(repoUrl string) bool {
@@ -67,46 +74,87 @@ func GetRepoName(repoPath string) string {
 	return strings.Split(repoPath, "/")[strings.Count(repoPath, "/")]
 }
 
+// azureDevOpsSshHost is the ssh host of dev.azure.com repos, ex: git@ssh.dev.azure.com:v3/org/project/repo
+const azureDevOpsSshHost = "ssh.dev.azure.com"
+
 func GetHostname(url string) string {
+	hostname := getHostname(url)
+	if hostname == azureDevOpsSshHost {
+		return strings.TrimPrefix(hostname, "ssh.")
+	}
+	return hostname
+}
+
+func getHostname(url string) string {
 	if url == "" {
 		return ""
 	}
 	if IsGitSshUrl(url) {
 		if strings.HasPrefix(url, "ssh://") {
-			return strings.Split(strings.Split(url, "@")[1], "/")[0]
+			hostname, _ := splitSshUrl(url)
+			return hostname
 		}
 		return strings.Split(strings.Split(url, "@")[1], ":")[0]
 	}
 	if IsGitHttpUrlHasUsername(url) {
 		return strings.Split(strings.Split(url, "@")[1], "/")[0]
 	}
-	return strings.Split(strings.Split(url, "://")[1], "/")[0]
+	_, rest, found := strings.Cut(url, "://")
+	if !found {
+		rest = url
+	}
+	return strings.Split(rest, "/")[0]
+}
+
+// splitSshUrl returns the hostname, without the user and the port, and the path of an ssh:// url.
+// ex: ssh://git@bitbucket.example.com:7999/proj/repo.git returns bitbucket.example.com and proj/repo.git
+func splitSshUrl(sshUrl string) (hostname, repoPath string) {
+	parsed, err := url.Parse(sshUrl)
+	if err != nil {
+		log.Debugf("failed to parse ssh url %s: %v", sshUrl, err)
+		rest := strings.TrimPrefix(sshUrl, "ssh://")
+		rest = rest[strings.Index(rest, "@")+1:]
+		hostAndPort, repoPath, _ := strings.Cut(rest, "/")
+		hostname, _, _ = strings.Cut(hostAndPort, ":")
+		return hostname, repoPath
+	}
+	return parsed.Hostname(), strings.TrimPrefix(parsed.Path, "/")
 }
 
+// GetRepoPath returns the path of the repo on the scm host from a clone url or a browser url
 func GetRepoPath(url, host string, p config.ScmProvider) (string, error) {
-	if IsGitUrl(url) {
-		return url[strings.Index(url, host)+1+len(host) : strings.Index(url, ".git")], nil
+	prov, err := provider.Get(p)
+	if err != nil {
+		return "", err
 	}
-	switch p {
-	case config.GitLab:
-		if strings.Contains(url, "/-/") {
-			return url[strings.Index(url, host)+1+len(host) : strings.Index(url, "/-/")], nil
-		} else {
-			return url[strings.Index(url, host)+1+len(host):], nil
-		}
-	case config.GitHub:
-		repoPath := url[strings.Index(url, host)+1+len(host):]
-		// GitHub web URL patterns that should be stripped to extract owner/repo
-		githubPatterns := []string{"/tree/", "/blob/", "/commits/", "/pull/", "/issues/", "/compare/"}
-		for _, pattern := range githubPatterns {
-			if idx := strings.Index(repoPath, pattern); idx != -1 {
-				return repoPath[:idx], nil
-			}
-		}
-		return repoPath, nil
-	default:
-		return "", errors.Errorf("provider %s not supported for browser urls", p)
+	if IsGitUrl(url) || IsGitSshUrl(url) {
+		return prov.ParseCloneURL(getCloneUrlPath(url, host))
+	}
+	repoPath, _, err := prov.ParseBrowserURL(url[strings.Index(url, host)+len(host):])
+	return repoPath, err
+}
+
+// getCloneUrlPath returns the path of a clone url, ex: owner/repo.git for git@github.com:owner/repo.git
+func getCloneUrlPath(url, host string) string {
+	if strings.HasPrefix(url, "ssh://") {
+		_, repoPath := splitSshUrl(url)
+		return repoPath
+	}
+	return strings.TrimLeft(url[strings.Index(url, host)+len(host):], ":/")
+}
+
+// GetRef returns the branch, tag or pull request the browser url points at
+// and nil when the url points at the repo itself
+func GetRef(url, host string, p config.ScmProvider) (*provider.Ref, error) {
+	if IsGitUrl(url) || IsGitSshUrl(url) || !strings.Contains(url, host) {
+		return nil, nil
+	}
+	prov, err := provider.Get(p)
+	if err != nil {
+		return nil, err
 	}
+	_, ref, err := prov.ParseBrowserURL(url[strings.Index(url, host)+len(host):])
+	return ref, err
 }
 
 func OpenInBrowser(url string) {
@@ -2,7 +2,9 @@ package url_test
 
 import (
 	"github.com/swarupdonepudi/gitr/pkg/config"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
 	"github.com/swarupdonepudi/gitr/pkg/url"
+	"strings"
 	"testing"
 )
 
@@ -46,6 +48,7 @@ func TestIsGitSshUrl(t *testing.T) {
 	}{
 		{"git@github.com:swarupdonepudi/gitr.git", true},
 		{"ssh://github.com/swarupdonepudi/gitr.git", true},
+		{"deploy@git.example.com:swarupdonepudi/gitr.git", true},
 	}
 	var negativeUrlTests = []struct {
 		url         string
@@ -53,6 +56,7 @@ func TestIsGitSshUrl(t *testing.T) {
 	}{
 		{"https://github.com/swarupdonepudi/gitr", false},
 		{"github.com:swarupdonepudi/gitr.git", false},
+		{"https://user@github.com/swarupdonepudi/gitr.git", false},
 	}
 	t.Run("urls prefixed with ssh or git should be git ssh urls", func(t *testing.T) {
 		for _, u := range positiveUrlTests {
@@ -183,4 +187,189 @@ func TestGetRepoPath(t *testing.T) {
 			}
 		}
 	})
+	var ownerRepoTests = []struct {
+		url      string
+		host     string
+		provider config.ScmProvider
+		expected string
+	}{
+		{"https://bitbucket.org/workspace/repo", "bitbucket.org", config.BitBucketCloud, "workspace/repo"},
+		{"https://bitbucket.org/workspace/repo/src/main/README.md", "bitbucket.org", config.BitBucketCloud, "workspace/repo"},
+		{"https://bitbucket.org/workspace/repo/pull-requests/12", "bitbucket.org", config.BitBucketCloud, "workspace/repo"},
+		{"https://bitbucket.example.com/projects/PROJ/repos/repo/browse", "bitbucket.example.com", config.BitBucketDatacenter, "proj/repo"},
+		{"https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/7/overview", "bitbucket.example.com", config.BitBucketDatacenter, "proj/repo"},
+		{"https://bitbucket.example.com/users/JDoe/repos/repo/browse", "bitbucket.example.com", config.BitBucketDatacenter, "~jdoe/repo"},
+		{"https://bitbucket.example.com/scm/proj/repo.git", "bitbucket.example.com", config.BitBucketDatacenter, "proj/repo"},
+		{"https://gitea.example.com/team/svc/src/branch/main/README.md", "gitea.example.com", config.Gitea, "team/svc"},
+		{"https://gitea.example.com/team/svc/pulls/3", "gitea.example.com", config.Gitea, "team/svc"},
+		{"https://dev.azure.com/org/project/_git/repo", "dev.azure.com", config.AzureDevOps, "org/project/repo"},
+		{"https://org@dev.azure.com/org/project/_git/repo", "dev.azure.com", config.AzureDevOps, "org/project/repo"},
+		{"https://dev.azure.com/org/project/_git/repo/pullrequest/12", "dev.azure.com", config.AzureDevOps, "org/project/repo"},
+		{"git@ssh.dev.azure.com:v3/org/project/repo", "dev.azure.com", config.AzureDevOps, "org/project/repo"},
+		{"https://review.example.com/a/platform/build", "review.example.com", config.Gerrit, "platform/build"},
+		{"https://review.example.com/platform/build.git", "review.example.com", config.Gerrit, "platform/build"},
+		{"ssh://jdoe@review.example.com:29418/platform/build", "review.example.com", config.Gerrit, "platform/build"},
+		{"https://review.example.com/c/platform/build/+/12345", "review.example.com", config.Gerrit, "platform/build"},
+		{"https://review.example.com/admin/repos/platform/build,branches", "review.example.com", config.Gerrit, "platform/build"},
+		{"https://review.example.com/plugins/gitiles/platform/build/+/refs/heads/main/core", "review.example.com", config.Gerrit, "platform/build"},
+		{"https://git.sr.ht/~user/repo", "git.sr.ht", config.SourceHut, "~user/repo"},
+		{"https://git.sr.ht/~user/repo/tree/main/item/README.md", "git.sr.ht", config.SourceHut, "~user/repo"},
+		{"git@git.sr.ht:~user/repo", "git.sr.ht", config.SourceHut, "~user/repo"},
+	}
+	t.Run("browser urls of other providers should extract the repo path of the clone urls", func(t *testing.T) {
+		for _, tc := range ownerRepoTests {
+			result, err := url.GetRepoPath(tc.url, tc.host, tc.provider)
+			if err != nil {
+				t.Errorf("unexpected error for url %s: %v", tc.url, err)
+				continue
+			}
+			if result != tc.expected {
+				t.Errorf("expected %s but got %s for url %s", tc.expected, result, tc.url)
+			}
+		}
+	})
+	t.Run("Bitbucket data center urls outside of a repo should fail", func(t *testing.T) {
+		if _, err := url.GetRepoPath("https://bitbucket.example.com/projects/PROJ", "bitbucket.example.com", config.BitBucketDatacenter); err == nil {
+			t.Errorf("expecting an error for a project url")
+		}
+	})
+	t.Run("ssh urls with a port should extract the repo path after the port", func(t *testing.T) {
+		result, err := url.GetRepoPath("ssh://git@bitbucket.example.com:7999/proj/repo.git", "bitbucket.example.com", config.BitBucketDatacenter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result != "proj/repo" {
+			t.Errorf("expected proj/repo but got %s", result)
+		}
+	})
+}
+
+func TestGetHostname(t *testing.T) {
+	var tests = []struct {
+		url      string
+		expected string
+	}{
+		{"git@github.com:owner/repo.git", "github.com"},
+		{"ssh://git@github.com/owner/repo.git", "github.com"},
+		{"ssh://github.com/owner/repo.git", "github.com"},
+		{"ssh://git@bitbucket.example.com:7999/proj/repo.git", "bitbucket.example.com"},
+		{"git@ssh.dev.azure.com:v3/org/project/repo", "dev.azure.com"},
+		{"https://org@dev.azure.com/org/project/_git/repo", "dev.azure.com"},
+		{"https://user@github.com/owner/repo.git", "github.com"},
+		{"https://github.com/owner/repo", "github.com"},
+		{"deploy@git.example.com:owner/repo.git", "git.example.com"},
+		{"github.com/owner/repo", "github.com"},
+	}
+	t.Run("hostname should not include the user or the ssh port", func(t *testing.T) {
+		for _, tc := range tests {
+			if result := url.GetHostname(tc.url); result != tc.expected {
+				t.Errorf("expected %s but got %s for url %s", tc.expected, result, tc.url)
+			}
+		}
+	})
+}
+
+func TestGetRef(t *testing.T) {
+	var tests = []struct {
+		url         string
+		host        string
+		provider    config.ScmProvider
+		kind        provider.RefKind
+		name        string
+		remoteRef   string
+		localBranch string
+	}{
+		{"https://github.com/owner/repo/tree/feature-x", "github.com", config.GitHub, provider.RefName, "feature-x", "", ""},
+		{"https://github.com/owner/repo/blob/main/docs/README.md", "github.com", config.GitHub, provider.RefName, "main/docs/README.md", "", ""},
+		{"https://github.com/owner/repo/pull/123", "github.com", config.GitHub, provider.RefPullRequest, "", "refs/pull/123/head", "pr-123"},
+		{"https://github.com/owner/repo/pull/123/files", "github.com", config.GitHub, provider.RefPullRequest, "", "refs/pull/123/head", "pr-123"},
+		{"https://gitlab.com/group/sub/proj/-/tree/release/1.0", "gitlab.com", config.GitLab, provider.RefName, "release/1.0", "", ""},
+		{"https://gitlab.com/group/proj/-/merge_requests/42", "gitlab.com", config.GitLab, provider.RefPullRequest, "", "refs/merge-requests/42/head", "mr-42"},
+		{"https://bitbucket.org/workspace/repo/src/feature-x/README.md", "bitbucket.org", config.BitBucketCloud, provider.RefName, "feature-x/README.md", "", ""},
+		{"https://bitbucket.example.com/projects/PROJ/repos/repo/pull-requests/7/overview", "bitbucket.example.com", config.BitBucketDatacenter, provider.RefPullRequest, "", "refs/pull-requests/7/from", "pr-7"},
+		{"https://gitea.example.com/team/svc/src/branch/feature-x/README.md", "gitea.example.com", config.Gitea, provider.RefName, "feature-x/README.md", "", ""},
+		{"https://gitea.example.com/team/svc/pulls/3/files", "gitea.example.com", config.Gitea, provider.RefPullRequest, "", "refs/pull/3/head", "pr-3"},
+		{"https://dev.azure.com/org/project/_git/repo/pullrequest/12", "dev.azure.com", config.AzureDevOps, provider.RefPullRequest, "", "refs/pull/12/merge", "pr-12"},
+		{"https://git.sr.ht/~user/repo/tree/v1.0/item/docs", "git.sr.ht", config.SourceHut, provider.RefName, "v1.0/item/docs", "", ""},
+	}
+	t.Run("browser urls should point at the ref", func(t *testing.T) {
+		for _, tc := range tests {
+			ref, err := url.GetRef(tc.url, tc.host, tc.provider)
+			if err != nil {
+				t.Errorf("unexpected error for url %s: %v", tc.url, err)
+				continue
+			}
+			if ref == nil {
+				t.Errorf("expecting a ref for url %s", tc.url)
+				continue
+			}
+			if ref.Kind != tc.kind || ref.Name != tc.name || ref.RemoteRef != tc.remoteRef || ref.LocalBranch != tc.localBranch {
+				t.Errorf("unexpected ref %+v for url %s", ref, tc.url)
+			}
+		}
+	})
+	var noRefUrls = []string{
+		"https://github.com/owner/repo",
+		"https://github.com/owner/repo.git",
+		"git@github.com:owner/repo.git",
+		"https://github.com/owner/repo/issues/456",
+	}
+	t.Run("urls of the repo itself should not point at a ref", func(t *testing.T) {
+		for _, u := range noRefUrls {
+			if ref, _ := url.GetRef(u, "github.com", config.GitHub); ref != nil {
+				t.Errorf("expecting no ref for url %s but got %+v", u, ref)
+			}
+		}
+	})
+	t.Run("candidates should be listed longest first", func(t *testing.T) {
+		ref := &provider.Ref{Kind: provider.RefName, Name: "feature/x/README.md"}
+		expected := []string{"feature/x/README.md", "feature/x", "feature"}
+		candidates := ref.Candidates()
+		if strings.Join(candidates, ",") != strings.Join(expected, ",") {
+			t.Errorf("expecting %v but got %v", expected, candidates)
+		}
+	})
+}
+
+func TestExpandShorthand(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cfg.Scm.Hosts = append(cfg.Scm.Hosts, &config.ScmHost{Hostname: "git.example.com", Provider: config.GitLab, Scheme: config.Http})
+	cfg.Aliases = map[string]string{"work": "git.example.com", "gh": "git.example.com"}
+	var tests = []struct {
+		ref      string
+		expected string
+	}{
+		{"owner/repo", "https://github.com/owner/repo"},
+		{"gl:group/sub/repo", "https://gitlab.com/group/sub/repo"},
+		{"work:team/svc", "http://git.example.com/team/svc"},
+		{"gh:owner/repo", "http://git.example.com/owner/repo"},
+		{"gitlab.com/group/repo", "https://gitlab.com/group/repo"},
+		{"https://github.com/owner/repo", "https://github.com/owner/repo"},
+		{"git@github.com:owner/repo.git", "git@github.com:owner/repo.git"},
+		{"/tmp/repo", "/tmp/repo"},
+	}
+	t.Run("shorthand refs should resolve through the aliases and the default host", func(t *testing.T) {
+		for _, tc := range tests {
+			result, err := url.ExpandShorthand(cfg, tc.ref)
+			if err != nil {
+				t.Errorf("unexpected error for %s: %v", tc.ref, err)
+				continue
+			}
+			if result != tc.expected {
+				t.Errorf("expected %s but got %s for %s", tc.expected, result, tc.ref)
+			}
+		}
+	})
+	t.Run("unknown aliases and refs without a repo path should fail", func(t *testing.T) {
+		for _, ref := range []string{"nope:owner/repo", "gh:owner/"} {
+			if _, err := url.ExpandShorthand(cfg, ref); err == nil {
+				t.Errorf("expecting an error for %s", ref)
+			}
+		}
+	})
+	t.Run("refs without a host should fail when there is no default host", func(t *testing.T) {
+		if _, err := url.ExpandShorthand(&config.GitrConfig{Scm: &config.Scm{}}, "owner/repo"); err == nil {
+			t.Errorf("expecting an error without a default host")
+		}
+	})
 }
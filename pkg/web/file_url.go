@@ -1,41 +1,16 @@
 package web
 
 import (
-	"fmt"
 	"github.com/pkg/errors"
 	gitrgit "github.com/swarupdonepudi/gitr/pkg/git"
+	"github.com/swarupdonepudi/gitr/pkg/provider"
 	"github.com/swarupdonepudi/gitr/pkg/url"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/swarupdonepudi/gitr/pkg/config"
 )
 
-// GetFileURL returns the browser URL for a single file in the repo.
-//
-//	base   – repo web URL, e.g. https://github.com/org/repo
-//	ref    – branch name or commit SHA
-//	rel    – path inside repo, **forward-slash** format
-//
-// Provider-specific rules:
-//
-//	GitHub             : <base>/blob/<ref>/<rel>
-//	GitLab             : <base>/-/blob/<ref>/<rel>
-//	Bitbucket Cloud/DC : <base>/src/<ref>/<rel>
-func GetFileURL(p config.ScmProvider, base, ref, rel string) string {
-	rel = strings.TrimPrefix(rel, "/") // safety
-
-	switch p {
-	case config.GitLab:
-		return fmt.Sprintf("%s/-/blob/%s/%s", base, ref, rel)
-	case config.BitBucketCloud, config.BitBucketDatacenter:
-		return fmt.Sprintf("%s/src/%s/%s", base, ref, rel)
-	default: // GitHub and similar
-		return fmt.Sprintf("%s/blob/%s/%s", base, ref, rel)
-	}
-}
-
 // FileURLFromPwd returns the provider-specific web URL for fileName,
 // where fileName is given **relative to the current working directory**.
 func FileURLFromPwd(fileName string) (string, error) {
@@ -69,7 +44,11 @@ func FileURLFromPwd(fileName string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	base := GetWebUrl(hostCfg.Provider, hostCfg.Scheme, host, repoPath)
+	prov, err := provider.ForRepo(hostCfg, repoPath)
+	if err != nil {
+		return "", err
+	}
+	base := prov.RepoWebURL(hostCfg.Scheme, host, repoPath)
 
 	// path inside repo
 	wt, _ := repo.Worktree()
@@ -80,5 +59,5 @@ func FileURLFromPwd(fileName string) (string, error) {
 	}
 
 	// final link
-	return GetFileURL(hostCfg.Provider, base, ref, filepath.ToSlash(rel)), nil
+	return prov.FileURL(base, ref, filepath.ToSlash(rel))
 }